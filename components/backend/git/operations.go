@@ -15,6 +15,8 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
 	"time"
 
@@ -24,6 +26,7 @@ import (
 	"k8s.io/client-go/kubernetes"
 
 	"ambient-code-backend/gitlab"
+	"ambient-code-backend/httpclient"
 	"ambient-code-backend/types"
 )
 
@@ -297,6 +300,52 @@ func ParseGitHubURL(gitURL string) (owner, repo string, err error) {
 	return "", "", fmt.Errorf("not a GitHub URL")
 }
 
+// ParseOwnerRepo extracts the owner and repository name from a Git URL in HTTPS
+// (https://host/owner/repo), scp-style SSH (git@host:owner/repo.git) or ssh:// form
+// A trailing .git and slashes are ignored. For nested GitLab groups the owner is the full
+// group path (group/sub). Returns an error when the URL has fewer than two path segments
+func ParseOwnerRepo(raw string) (owner, repo string, err error) {
+	s := strings.TrimSpace(raw)
+	var repoPath string
+	if strings.Contains(s, "://") {
+		u, err := url.Parse(s)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid repository URL %q: %w", raw, err)
+		}
+		switch strings.ToLower(u.Scheme) {
+		case "https", "http", "ssh":
+		default:
+			return "", "", fmt.Errorf("unsupported scheme in repository URL %q", raw)
+		}
+		repoPath = u.Path
+	} else {
+		// scp-style user@host:path; the host may be a bracketed IPv6 address
+		at := strings.Index(s, "@")
+		if at < 0 {
+			return "", "", fmt.Errorf("unsupported repository URL %q: expected https://, ssh:// or user@host:path", raw)
+		}
+		rest := s[at+1:]
+		hostEnd := 0
+		if strings.HasPrefix(rest, "[") {
+			hostEnd = max(strings.Index(rest, "]"), 0)
+		}
+		i := strings.Index(rest[hostEnd:], ":")
+		if i < 0 {
+			return "", "", fmt.Errorf("unsupported repository URL %q: expected https://, ssh:// or user@host:path", raw)
+		}
+		repoPath = rest[hostEnd+i+1:]
+	}
+
+	repoPath = strings.Trim(repoPath, "/")
+	repoPath = strings.TrimSuffix(repoPath, ".git")
+	repoPath = strings.Trim(repoPath, "/")
+	i := strings.LastIndex(repoPath, "/")
+	if i < 0 || slices.Contains(strings.Split(repoPath, "/"), "") {
+		return "", "", fmt.Errorf("repository URL %q does not have an owner/repo path", raw)
+	}
+	return repoPath[:i], repoPath[i+1:], nil
+}
+
 // IsProtectedBranch checks if a branch name is a protected branch
 // Protected branches: main, master, develop
 func IsProtectedBranch(branchName string) bool {
@@ -323,6 +372,50 @@ func ValidateBranchName(branchName string) error {
 	return nil
 }
 
+// ValidateRefName checks name against git's branch naming rules (git check-ref-format --branch)
+// Unlike ValidateBranchName it does not reject protected branches: it is for refs that are read, not pushed
+func ValidateRefName(name string) error {
+	switch {
+	case strings.TrimSpace(name) == "":
+		return fmt.Errorf("ref name cannot be empty")
+	case name == "@":
+		return fmt.Errorf("ref name %q is not allowed", name)
+	case strings.HasPrefix(name, "-"):
+		return fmt.Errorf("ref name %q cannot start with '-'", name)
+	case strings.HasPrefix(name, "/") || strings.HasSuffix(name, "/") || strings.Contains(name, "//"):
+		return fmt.Errorf("ref name %q has an empty path component", name)
+	case strings.HasSuffix(name, "."):
+		return fmt.Errorf("ref name %q cannot end with '.'", name)
+	case strings.Contains(name, ".."):
+		return fmt.Errorf("ref name %q cannot contain '..'", name)
+	case strings.Contains(name, "@{"):
+		return fmt.Errorf("ref name %q cannot contain '@{'", name)
+	}
+	for _, r := range name {
+		if r < 0x20 || r == 0x7f || strings.ContainsRune(" ~^:?*[\\", r) {
+			return fmt.Errorf("ref name %q contains invalid character %q", name, r)
+		}
+	}
+	for _, component := range strings.Split(name, "/") {
+		if strings.HasPrefix(component, ".") || strings.HasSuffix(component, ".lock") {
+			return fmt.Errorf("ref name %q has a component starting with '.' or ending with '.lock'", name)
+		}
+	}
+	return nil
+}
+
+// commitSHARegex matches a full SHA-1 (40) or SHA-256 (64) object name
+var commitSHARegex = regexp.MustCompile(`^([0-9a-fA-F]{40}|[0-9a-fA-F]{64})$`)
+
+// ValidateCommitSHA checks sha is a full hex commit ID
+// Abbreviated IDs are rejected: a remote can only be asked for a commit by its full name
+func ValidateCommitSHA(sha string) error {
+	if !commitSHARegex.MatchString(sha) {
+		return fmt.Errorf("commit %q must be a full 40- or 64-character hex SHA", sha)
+	}
+	return nil
+}
+
 // checkGitHubPathExists checks if a path exists in a GitHub repo
 func checkGitHubPathExists(ctx context.Context, owner, repo, branch, path, token string) (bool, error) {
 	apiURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/contents/%s?ref=%s",
@@ -336,7 +429,7 @@ func checkGitHubPathExists(ctx context.Context, owner, repo, branch, path, token
 	req.Header.Set("Authorization", "Bearer "+token)
 	req.Header.Set("Accept", "application/vnd.github.v3+json")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := httpclient.NewClient(httpclient.Options{}).Do(req)
 	if err != nil {
 		return false, err
 	}
@@ -534,7 +627,7 @@ func PerformRepoSeeding(ctx context.Context, wf Workflow, branchName, token, age
 		log.Printf("Downloading spec-kit branch archive: %s", specKitURL)
 	}
 
-	resp, err := http.Get(specKitURL)
+	resp, err := httpclient.NewClient(httpclient.Options{Timeout: 2 * time.Minute}).Get(specKitURL)
 	if err != nil {
 		return false, fmt.Errorf("failed to download spec-kit: %w", err)
 	}
@@ -794,7 +887,25 @@ func InjectGitLabToken(gitURL, token string) (string, error) {
 	return u.String(), nil
 }
 
+// InjectGenericToken injects a token as the username (token@host), for hosts of unknown provider
+func InjectGenericToken(gitURL, token string) (string, error) {
+	u, err := url.Parse(gitURL)
+	if err != nil {
+		// Sanitize URL before including in error message
+		return "", fmt.Errorf("invalid git URL (%s): %w", sanitizeURLForError(gitURL), err)
+	}
+
+	if u.Scheme != "https" {
+		return gitURL, nil
+	}
+
+	u.User = url.User(token)
+	return u.String(), nil
+}
+
 // InjectGitToken injects a Git token into a URL based on the repository provider
+// GitHub hosts, including GitHub Enterprise hosts configured with types.SetProviderHosts, use the
+// x-access-token form, GitLab hosts the oauth2 form, and any other host the generic token@host form
 func InjectGitToken(gitURL, token string) (string, error) {
 	provider := types.DetectProvider(gitURL)
 
@@ -804,7 +915,7 @@ func InjectGitToken(gitURL, token string) (string, error) {
 	case types.ProviderGitLab:
 		return InjectGitLabToken(gitURL, token)
 	default:
-		return "", fmt.Errorf("unsupported repository provider for URL: %s", gitURL)
+		return InjectGenericToken(gitURL, token)
 	}
 }
 
@@ -1017,7 +1128,7 @@ func PushRepo(ctx context.Context, repoDir, commitMessage, outputRepoURL, branch
 		req, _ := http.NewRequest("GET", "https://api.github.com/user", nil)
 		req.Header.Set("Authorization", "token "+githubToken)
 		req.Header.Set("Accept", "application/vnd.github+json")
-		resp, err := http.DefaultClient.Do(req)
+		resp, err := httpclient.NewClient(httpclient.Options{}).Do(req)
 		if err == nil {
 			defer resp.Body.Close()
 			switch resp.StatusCode {
@@ -1243,7 +1354,7 @@ func ReadGitHubFile(ctx context.Context, owner, repo, branch, path, token string
 	req.Header.Set("Authorization", "Bearer "+token)
 	req.Header.Set("Accept", "application/vnd.github.v3.raw")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := httpclient.NewClient(httpclient.Options{}).Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -1275,7 +1386,7 @@ func CheckBranchExists(ctx context.Context, repoURL, branchName, githubToken str
 	req.Header.Set("Authorization", "Bearer "+githubToken)
 	req.Header.Set("Accept", "application/vnd.github.v3+json")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := httpclient.NewClient(httpclient.Options{}).Do(req)
 	if err != nil {
 		return false, err
 	}
@@ -1325,7 +1436,7 @@ func validateGitHubPushAccess(ctx context.Context, repoURL, githubToken string)
 	req.Header.Set("Authorization", "Bearer "+githubToken)
 	req.Header.Set("Accept", "application/vnd.github.v3+json")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := httpclient.NewClient(httpclient.Options{}).Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to check repository access: %w", err)
 	}
@@ -1394,7 +1505,7 @@ func validateGitLabPushAccess(ctx context.Context, repoURL, gitlabToken string)
 	req.Header.Set("Authorization", "Bearer "+gitlabToken)
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := httpclient.NewClient(httpclient.Options{}).Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to check repository access: %w", err)
 	}
@@ -1457,7 +1568,7 @@ func validateGitLabPushAccess(ctx context.Context, repoURL, gitlabToken string)
 		userReq.Header.Set("Authorization", "Bearer "+gitlabToken)
 		userReq.Header.Set("Accept", "application/json")
 
-		userResp, err := http.DefaultClient.Do(userReq)
+		userResp, err := httpclient.NewClient(httpclient.Options{}).Do(userReq)
 		if err != nil {
 			return fmt.Errorf("failed to get user info: %w", err)
 		}
@@ -1759,7 +1870,7 @@ func configureGitIdentity(ctx context.Context, repoDir, githubToken string) {
 		req.Header.Set("Authorization", "token "+githubToken)
 		req.Header.Set("Accept", "application/vnd.github+json")
 
-		if resp, err := http.DefaultClient.Do(req); err == nil {
+		if resp, err := httpclient.NewClient(httpclient.Options{}).Do(req); err == nil {
 			defer resp.Body.Close()
 			if resp.StatusCode == 200 {
 				if body, err := io.ReadAll(resp.Body); err == nil {
@@ -0,0 +1,145 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"ambient-code-backend/types"
+)
+
+// Preflight failures returned by PreflightRepo; match them with errors.Is
+var (
+	// ErrRepoAuth means the credentials were missing, invalid or lack read access
+	ErrRepoAuth = errors.New("repository authentication failed")
+	// ErrRepoNotFound means the repository, or the requested branch or tag, does not exist
+	ErrRepoNotFound = errors.New("repository or ref not found")
+	// ErrRepoUnreachable means the host could not be reached or did not answer in time
+	ErrRepoUnreachable = errors.New("repository host unreachable")
+)
+
+// PreflightTimeout bounds a single PreflightRepo call
+var PreflightTimeout = 20 * time.Second
+
+// lsRemoteExitNoRefs is the exit status of `git ls-remote --exit-code` when no ref matched
+const lsRemoteExitNoRefs = 2
+
+// runLsRemote runs git with args and returns its combined output
+// Replaced in tests so no real remote is contacted
+var runLsRemote = func(ctx context.Context, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	// Fail instead of prompting for credentials
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GIT_ASKPASS=")
+	return cmd.CombinedOutput()
+}
+
+// PreflightRepo checks that loc is reachable with token before a session starts
+// It runs `git ls-remote` for the location's branch or tag (commits are not advertised,
+// so only repository access is checked for them), bounded by PreflightTimeout
+// Failures wrap ErrRepoAuth, ErrRepoNotFound or ErrRepoUnreachable; cancellation of ctx
+// is returned as ctx's error. The token never appears in returned errors
+func PreflightRepo(ctx context.Context, loc types.RepoLocation, token string) error {
+	safeURL := sanitizeURLForError(loc.URL)
+
+	remote := loc.URL
+	if token != "" {
+		var err error
+		if remote, err = InjectGitToken(loc.URL, token); err != nil {
+			return err
+		}
+	}
+
+	args := []string{"ls-remote", "--exit-code", remote}
+	if ref := preflightRef(loc); ref != "" {
+		args = append(args, ref)
+	} else {
+		args = append(args, "HEAD")
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, PreflightTimeout)
+	defer cancel()
+
+	out, err := runLsRemote(callCtx, args...)
+	if err == nil {
+		return nil
+	}
+	if ctx.Err() != nil {
+		return fmt.Errorf("preflight of %s cancelled: %w", safeURL, ctx.Err())
+	}
+	if callCtx.Err() != nil {
+		return fmt.Errorf("%w: %s did not respond within %s", ErrRepoUnreachable, safeURL, PreflightTimeout)
+	}
+
+	var exitErr interface{ ExitCode() int }
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == lsRemoteExitNoRefs {
+		return fmt.Errorf("%w: %s has no ref %s", ErrRepoNotFound, safeURL, args[len(args)-1])
+	}
+
+	detail := redactToken(strings.TrimSpace(string(out)), token)
+	if len(detail) > 500 {
+		detail = detail[:500] + "..."
+	}
+	if kind := classifyLsRemoteOutput(detail); kind != nil {
+		return fmt.Errorf("%w: %s: %s", kind, safeURL, detail)
+	}
+	return fmt.Errorf("git ls-remote %s failed: %s", safeURL, detail)
+}
+
+// preflightRef returns the fully qualified ref to look up for loc, or "" for commits and unset refs
+func preflightRef(loc types.RepoLocation) string {
+	kind, value, err := loc.CheckoutRef()
+	if err != nil {
+		return ""
+	}
+	switch kind {
+	case types.RefKindTag:
+		return "refs/tags/" + value
+	case types.RefKindBranch:
+		if loc.PullRequest || strings.HasPrefix(value, "refs/") {
+			return value
+		}
+		return "refs/heads/" + value
+	default:
+		return ""
+	}
+}
+
+// classifyLsRemoteOutput maps git's error output to a preflight error, or nil if unrecognized
+// Auth is checked first: hosts often answer bad credentials for private repos with "not found"
+func classifyLsRemoteOutput(output string) error {
+	lower := strings.ToLower(output)
+	switch {
+	case containsAny(lower, "authentication failed", "could not read username", "invalid username or password",
+		"permission denied", "403", "401"):
+		return ErrRepoAuth
+	case containsAny(lower, "repository not found", "not found", "404", "does not appear to be a git repository"):
+		return ErrRepoNotFound
+	case containsAny(lower, "could not resolve host", "connection refused", "connection timed out", "timed out",
+		"network is unreachable", "no route to host", "failed to connect"):
+		return ErrRepoUnreachable
+	default:
+		return nil
+	}
+}
+
+// containsAny reports whether s contains any of substrs
+func containsAny(s string, substrs ...string) bool {
+	for _, sub := range substrs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactToken replaces every occurrence of token in s
+func redactToken(s, token string) string {
+	if token == "" {
+		return s
+	}
+	return strings.ReplaceAll(s, token, "[REDACTED]")
+}
@@ -0,0 +1,152 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"ambient-code-backend/types"
+)
+
+// exitError mimics *exec.ExitError for a stubbed git command
+type exitError struct{ code int }
+
+func (e exitError) Error() string { return fmt.Sprintf("exit status %d", e.code) }
+func (e exitError) ExitCode() int { return e.code }
+
+// stubLsRemote replaces runLsRemote for the duration of the test and records the args it saw
+func stubLsRemote(t *testing.T, fn func(ctx context.Context) ([]byte, error)) *[]string {
+	t.Helper()
+	var seen []string
+	orig := runLsRemote
+	runLsRemote = func(ctx context.Context, args ...string) ([]byte, error) {
+		seen = args
+		return fn(ctx)
+	}
+	t.Cleanup(func() { runLsRemote = orig })
+	return &seen
+}
+
+const testToken = "ghs_supersecrettoken"
+
+func TestPreflightRepoOutcomes(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		err     error
+		wantErr error
+	}{
+		{name: "reachable", output: "abc123\trefs/heads/main\n"},
+		{name: "bad credentials", output: "remote: Invalid username or password.\nfatal: Authentication failed for 'https://github.com/owner/repo.git/'", err: exitError{128}, wantErr: ErrRepoAuth},
+		{name: "forbidden", output: "fatal: unable to access 'https://github.com/owner/repo.git/': The requested URL returned error: 403", err: exitError{128}, wantErr: ErrRepoAuth},
+		{name: "missing repository", output: "remote: Repository not found.\nfatal: repository 'https://github.com/owner/repo.git/' not found", err: exitError{128}, wantErr: ErrRepoNotFound},
+		{name: "missing branch", err: exitError{lsRemoteExitNoRefs}, wantErr: ErrRepoNotFound},
+		{name: "unknown host", output: "fatal: unable to access 'https://github.com/owner/repo.git/': Could not resolve host: github.com", err: exitError{128}, wantErr: ErrRepoUnreachable},
+		{name: "connection refused", output: "fatal: unable to access 'https://github.com/owner/repo.git/': Failed to connect to github.com port 443: Connection refused", err: exitError{128}, wantErr: ErrRepoUnreachable},
+	}
+
+	loc := types.RepoLocation{URL: "https://github.com/owner/repo.git", Branch: types.StringPtr("main")}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stubLsRemote(t, func(ctx context.Context) ([]byte, error) {
+				return []byte(tt.output), tt.err
+			})
+
+			err := PreflightRepo(context.Background(), loc, testToken)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("PreflightRepo() unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("PreflightRepo() error = %v, want %v", err, tt.wantErr)
+			}
+			for _, other := range []error{ErrRepoAuth, ErrRepoNotFound, ErrRepoUnreachable} {
+				if other != tt.wantErr && errors.Is(err, other) {
+					t.Errorf("PreflightRepo() error also matches %v", other)
+				}
+			}
+		})
+	}
+}
+
+func TestPreflightRepoRefArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		loc     types.RepoLocation
+		wantRef string
+	}{
+		{name: "branch", loc: types.RepoLocation{URL: "https://github.com/owner/repo.git", Branch: types.StringPtr("main")}, wantRef: "refs/heads/main"},
+		{name: "tag", loc: types.RepoLocation{URL: "https://github.com/owner/repo.git", Tag: types.StringPtr("v1.2.0")}, wantRef: "refs/tags/v1.2.0"},
+		{name: "pull request", loc: types.RepoLocation{URL: "https://github.com/owner/repo.git", Branch: types.StringPtr("refs/pull/42/head"), PullRequest: true}, wantRef: "refs/pull/42/head"},
+		{name: "commit checks repository only", loc: types.RepoLocation{URL: "https://github.com/owner/repo.git", Commit: types.StringPtr("abc123")}, wantRef: "HEAD"},
+		{name: "no ref", loc: types.RepoLocation{URL: "https://gitlab.com/group/repo.git"}, wantRef: "HEAD"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := stubLsRemote(t, func(ctx context.Context) ([]byte, error) { return nil, nil })
+
+			if err := PreflightRepo(context.Background(), tt.loc, testToken); err != nil {
+				t.Fatalf("PreflightRepo() unexpected error: %v", err)
+			}
+			got := *args
+			if len(got) != 4 || got[0] != "ls-remote" || got[1] != "--exit-code" {
+				t.Fatalf("args = %v, want [ls-remote --exit-code <url> <ref>]", got)
+			}
+			if !strings.Contains(got[2], testToken) {
+				t.Errorf("remote URL %q should carry the token", got[2])
+			}
+			if got[3] != tt.wantRef {
+				t.Errorf("ref = %q, want %q", got[3], tt.wantRef)
+			}
+		})
+	}
+}
+
+func TestPreflightRepoNeverLeaksToken(t *testing.T) {
+	stubLsRemote(t, func(ctx context.Context) ([]byte, error) {
+		return []byte("fatal: Authentication failed for 'https://x-access-token:" + testToken + "@github.com/owner/repo.git/'"), exitError{128}
+	})
+
+	err := PreflightRepo(context.Background(), types.RepoLocation{URL: "https://github.com/owner/repo.git"}, testToken)
+	if !errors.Is(err, ErrRepoAuth) {
+		t.Fatalf("PreflightRepo() error = %v, want ErrRepoAuth", err)
+	}
+	if strings.Contains(err.Error(), testToken) {
+		t.Errorf("error leaks the token: %v", err)
+	}
+}
+
+func TestPreflightRepoHonorsCancellation(t *testing.T) {
+	stubLsRemote(t, func(ctx context.Context) ([]byte, error) {
+		<-ctx.Done()
+		return nil, errors.New("signal: killed")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := PreflightRepo(ctx, types.RepoLocation{URL: "https://github.com/owner/repo.git"}, "")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("PreflightRepo() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestPreflightRepoTimesOut(t *testing.T) {
+	orig := PreflightTimeout
+	PreflightTimeout = 10 * time.Millisecond
+	t.Cleanup(func() { PreflightTimeout = orig })
+	stubLsRemote(t, func(ctx context.Context) ([]byte, error) {
+		<-ctx.Done()
+		return nil, errors.New("signal: killed")
+	})
+
+	err := PreflightRepo(context.Background(), types.RepoLocation{URL: "https://github.com/owner/repo.git"}, "")
+	if !errors.Is(err, ErrRepoUnreachable) {
+		t.Fatalf("PreflightRepo() error = %v, want ErrRepoUnreachable", err)
+	}
+}
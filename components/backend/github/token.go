@@ -8,6 +8,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"encoding/pem"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -16,17 +17,37 @@ import (
 	"sync"
 	"time"
 
+	"ambient-code-backend/handlers"
+	"ambient-code-backend/httpclient"
+
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// Retry settings for installation token minting (variables so tests can shorten them)
+var (
+	mintRetryAttempts     = 3
+	mintRetryInitialDelay = 500 * time.Millisecond
+	mintRetryMaxDelay     = 4 * time.Second
+)
+
 // TokenManager manages GitHub App installation tokens
 type TokenManager struct {
 	AppID      string
 	PrivateKey *rsa.PrivateKey
+	// HTTPClient is used for GitHub API calls; nil uses a default client with a 15s timeout
+	HTTPClient *http.Client
 	cacheMu    *sync.Mutex
 	cache      map[int64]cachedInstallationToken
 }
 
+// transientMintError marks token mint failures that are worth retrying (network errors, 429, 5xx)
+type transientMintError struct {
+	err error
+}
+
+func (e *transientMintError) Error() string { return e.err.Error() }
+func (e *transientMintError) Unwrap() error { return e.err }
+
 type cachedInstallationToken struct {
 	token     string
 	expiresAt time.Time
@@ -54,9 +75,22 @@ func NewTokenManager() (*TokenManager, error) {
 		}
 		pemBytes = decoded
 	}
-	privateKey, perr := parsePrivateKeyPEM(pemBytes)
-	if perr != nil {
-		return nil, fmt.Errorf("failed to parse GITHUB_PRIVATE_KEY: %w", perr)
+	manager, err := NewTokenManagerFromKey(appID, pemBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse GITHUB_PRIVATE_KEY: %w", err)
+	}
+	return manager, nil
+}
+
+// NewTokenManagerFromKey creates a token manager from an app ID and a PEM-encoded private key
+// Used for org-wide automation that authenticates as a GitHub App without relying on env vars
+func NewTokenManagerFromKey(appID string, privateKeyPEM []byte) (*TokenManager, error) {
+	if strings.TrimSpace(appID) == "" {
+		return nil, fmt.Errorf("GitHub App ID is required")
+	}
+	privateKey, err := parsePrivateKeyPEM(privateKeyPEM)
+	if err != nil {
+		return nil, err
 	}
 
 	return &TokenManager{
@@ -67,6 +101,14 @@ func NewTokenManager() (*TokenManager, error) {
 	}, nil
 }
 
+// httpClient returns the configured HTTP client or a default one
+func (m *TokenManager) httpClient() *http.Client {
+	if m.HTTPClient != nil {
+		return m.HTTPClient
+	}
+	return httpclient.NewClient(httpclient.Options{Timeout: 15 * time.Second})
+}
+
 // loadPrivateKey loads the RSA private key from a PEM file
 func parsePrivateKeyPEM(keyData []byte) (*rsa.PrivateKey, error) {
 	block, _ := pem.Decode(keyData)
@@ -129,6 +171,35 @@ func (m *TokenManager) MintInstallationTokenForHost(ctx context.Context, install
 	}
 	m.cacheMu.Unlock()
 
+	// Retry transient failures; the token itself is never logged or included in errors
+	var token string
+	var expiresAt time.Time
+	err := handlers.RetryWithOptions(ctx, handlers.RetryOptions{
+		MaxRetries:   mintRetryAttempts,
+		InitialDelay: mintRetryInitialDelay,
+		MaxDelay:     mintRetryMaxDelay,
+		Operation:    "github-token-mint",
+		Retryable: func(err error) bool {
+			var transient *transientMintError
+			return errors.As(err, &transient)
+		},
+	}, func() error {
+		var mintErr error
+		token, expiresAt, mintErr = m.requestInstallationToken(ctx, installationID, host)
+		return mintErr
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	m.cacheMu.Lock()
+	m.cache[installationID] = cachedInstallationToken{token: token, expiresAt: expiresAt}
+	m.cacheMu.Unlock()
+	return token, expiresAt, nil
+}
+
+// requestInstallationToken performs a single JWT -> installation token exchange
+func (m *TokenManager) requestInstallationToken(ctx context.Context, installationID int64, host string) (string, time.Time, error) {
 	jwtToken, err := m.GenerateJWT()
 	if err != nil {
 		return "", time.Time{}, fmt.Errorf("failed to generate JWT: %w", err)
@@ -146,15 +217,18 @@ func (m *TokenManager) MintInstallationTokenForHost(ctx context.Context, install
 	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
 	req.Header.Set("User-Agent", "vTeam-Backend")
 
-	client := &http.Client{Timeout: 15 * time.Second}
-	resp, err := client.Do(req)
+	resp, err := m.httpClient().Do(req)
 	if err != nil {
-		return "", time.Time{}, fmt.Errorf("failed to call GitHub: %w", err)
+		return "", time.Time{}, &transientMintError{fmt.Errorf("failed to call GitHub: %w", err)}
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(resp.Body)
-		return "", time.Time{}, fmt.Errorf("GitHub token mint failed: %s", string(body))
+		mintErr := fmt.Errorf("GitHub token mint failed (status %d): %s", resp.StatusCode, string(body))
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+			return "", time.Time{}, &transientMintError{mintErr}
+		}
+		return "", time.Time{}, mintErr
 	}
 	var parsed struct {
 		Token     string    `json:"token"`
@@ -163,9 +237,6 @@ func (m *TokenManager) MintInstallationTokenForHost(ctx context.Context, install
 	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
 		return "", time.Time{}, fmt.Errorf("failed to parse token response: %w", err)
 	}
-	m.cacheMu.Lock()
-	m.cache[installationID] = cachedInstallationToken{token: parsed.Token, expiresAt: parsed.ExpiresAt}
-	m.cacheMu.Unlock()
 	return parsed.Token, parsed.ExpiresAt, nil
 }
 
@@ -209,8 +280,7 @@ func (m *TokenManager) ValidateInstallationAccess(ctx context.Context, installat
 	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
 	req.Header.Set("User-Agent", "vTeam-Backend")
 
-	client := &http.Client{Timeout: 15 * time.Second}
-	resp, err := client.Do(req)
+	resp, err := m.httpClient().Do(req)
 	if err != nil {
 		return fmt.Errorf("GitHub request failed: %w", err)
 	}
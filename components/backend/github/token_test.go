@@ -0,0 +1,159 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// roundTripFunc stubs an http.RoundTripper
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func jsonResponse(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func newTestTokenManager(t *testing.T, rt roundTripFunc) *TokenManager {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	m, err := NewTokenManagerFromKey("12345", keyPEM)
+	if err != nil {
+		t.Fatalf("NewTokenManagerFromKey() error: %v", err)
+	}
+	m.HTTPClient = &http.Client{Transport: rt}
+	return m
+}
+
+func shortenMintRetries(t *testing.T) {
+	t.Helper()
+	attempts, initial, max := mintRetryAttempts, mintRetryInitialDelay, mintRetryMaxDelay
+	mintRetryAttempts, mintRetryInitialDelay, mintRetryMaxDelay = 3, time.Millisecond, time.Millisecond
+	t.Cleanup(func() {
+		mintRetryAttempts, mintRetryInitialDelay, mintRetryMaxDelay = attempts, initial, max
+	})
+}
+
+func TestMintInstallationTokenCachesUntilNearExpiry(t *testing.T) {
+	var calls int32
+	expiry := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	m := newTestTokenManager(t, func(req *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&calls, 1)
+		if req.URL.String() != "https://api.github.com/app/installations/42/access_tokens" {
+			t.Errorf("unexpected URL %s", req.URL)
+		}
+		if !strings.HasPrefix(req.Header.Get("Authorization"), "Bearer ") {
+			t.Errorf("expected JWT bearer auth")
+		}
+		return jsonResponse(http.StatusCreated, `{"token":"ghs_canned","expires_at":"`+expiry.Format(time.RFC3339)+`"}`), nil
+	})
+
+	for i := 0; i < 2; i++ {
+		token, exp, err := m.MintInstallationToken(context.Background(), 42)
+		if err != nil {
+			t.Fatalf("MintInstallationToken() error: %v", err)
+		}
+		if token != "ghs_canned" || !exp.Equal(expiry) {
+			t.Fatalf("MintInstallationToken() = (%q, %v), want (ghs_canned, %v)", token, exp, expiry)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 HTTP call with caching, got %d", calls)
+	}
+}
+
+func TestMintInstallationTokenRefreshesNearExpiry(t *testing.T) {
+	var calls int32
+	m := newTestTokenManager(t, func(req *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&calls, 1)
+		exp := time.Now().Add(time.Minute).UTC().Format(time.RFC3339)
+		return jsonResponse(http.StatusCreated, `{"token":"ghs_short","expires_at":"`+exp+`"}`), nil
+	})
+
+	for i := 0; i < 2; i++ {
+		if _, _, err := m.MintInstallationToken(context.Background(), 7); err != nil {
+			t.Fatalf("MintInstallationToken() error: %v", err)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("expected a refresh for a token about to expire, got %d calls", calls)
+	}
+}
+
+func TestMintInstallationTokenRetriesTransientFailures(t *testing.T) {
+	shortenMintRetries(t)
+	var logBuf bytes.Buffer
+	log.SetOutput(&logBuf)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	var calls int32
+	m := newTestTokenManager(t, func(req *http.Request) (*http.Response, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			return jsonResponse(http.StatusServiceUnavailable, `{"message":"unavailable"}`), nil
+		}
+		exp := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+		return jsonResponse(http.StatusCreated, `{"token":"ghs_after_retry","expires_at":"`+exp+`"}`), nil
+	})
+
+	token, _, err := m.MintInstallationToken(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("MintInstallationToken() error: %v", err)
+	}
+	if token != "ghs_after_retry" {
+		t.Errorf("token = %q, want ghs_after_retry", token)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 HTTP calls, got %d", calls)
+	}
+	if strings.Contains(logBuf.String(), "ghs_") {
+		t.Errorf("minted token leaked into logs: %s", logBuf.String())
+	}
+}
+
+func TestMintInstallationTokenDoesNotRetryClientErrors(t *testing.T) {
+	shortenMintRetries(t)
+	var calls int32
+	m := newTestTokenManager(t, func(req *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&calls, 1)
+		return jsonResponse(http.StatusUnauthorized, `{"message":"bad credentials"}`), nil
+	})
+
+	if _, _, err := m.MintInstallationToken(context.Background(), 1); err == nil {
+		t.Fatal("expected an error for a 401 response")
+	}
+	if calls != 1 {
+		t.Errorf("expected no retries for a 401, got %d calls", calls)
+	}
+}
+
+func TestNewTokenManagerFromKeyRejectsBadInput(t *testing.T) {
+	if _, err := NewTokenManagerFromKey("", []byte("irrelevant")); err == nil {
+		t.Error("expected an error for a missing app ID")
+	}
+	if _, err := NewTokenManagerFromKey("1", []byte("not a pem")); err == nil {
+		t.Error("expected an error for an invalid key")
+	}
+}
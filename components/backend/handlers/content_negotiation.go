@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"bytes"
+	"log"
+	"mime"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"sigs.k8s.io/yaml"
+)
+
+// YAMLContentType is the media type clients send in Accept to get YAML instead of JSON
+const YAMLContentType = "application/yaml"
+
+// yamlMediaTypes are the Accept values treated as a request for YAML
+var yamlMediaTypes = []string{YAMLContentType, "application/x-yaml", "text/yaml"}
+
+// ContentNegotiationMiddleware re-encodes JSON responses as YAML when the client prefers YAML
+// Handlers keep calling c.JSON; the body is buffered and converted after the handler returns,
+// so YAML uses the same json tags and error responses are negotiated too
+// Responses that are not application/json (NDJSON, event streams, files) pass through untouched
+func ContentNegotiationMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !prefersYAML(c.GetHeader("Accept")) {
+			c.Next()
+			return
+		}
+
+		w := &yamlResponseWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+
+		if !w.buffering {
+			return
+		}
+		body, err := yaml.JSONToYAML(w.body.Bytes())
+		if err != nil {
+			// Fall back to the JSON the handler produced rather than failing the request
+			log.Printf("%sContentNegotiation: failed to convert response to YAML: %v", requestIDLogPrefix(c.Request.Context()), err)
+			body = w.body.Bytes()
+		} else {
+			w.Header().Set("Content-Type", YAMLContentType+"; charset=utf-8")
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		if _, err := w.ResponseWriter.Write(body); err != nil {
+			log.Printf("%sContentNegotiation: failed to write response: %v", requestIDLogPrefix(c.Request.Context()), err)
+		}
+	}
+}
+
+// prefersYAML reports whether accept lists a YAML media type before application/json
+// q-values are not weighed; the first recognized type wins
+func prefersYAML(accept string) bool {
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		if mediaType == "application/json" {
+			return false
+		}
+		for _, t := range yamlMediaTypes {
+			if mediaType == t {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// yamlResponseWriter holds back JSON bodies so ContentNegotiationMiddleware can convert them
+// The decision is made on the first write, once the handler has set Content-Type
+type yamlResponseWriter struct {
+	gin.ResponseWriter
+	body      bytes.Buffer
+	decided   bool
+	buffering bool
+}
+
+func (w *yamlResponseWriter) Write(data []byte) (int, error) {
+	if !w.decided {
+		w.decided = true
+		mediaType, _, _ := mime.ParseMediaType(w.Header().Get("Content-Type"))
+		w.buffering = mediaType == "application/json"
+	}
+	if w.buffering {
+		return w.body.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *yamlResponseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Written reports true once a buffered body exists so handlers and gin do not write a second response
+func (w *yamlResponseWriter) Written() bool {
+	return w.buffering || w.ResponseWriter.Written()
+}
+
+// Flush is a no-op while buffering; the converted body is written in one piece at the end
+func (w *yamlResponseWriter) Flush() {
+	if !w.buffering {
+		w.ResponseWriter.Flush()
+	}
+}
@@ -0,0 +1,106 @@
+//go:build test
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	test_constants "ambient-code-backend/tests/constants"
+	"ambient-code-backend/types"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/yaml"
+)
+
+var _ = Describe("Content negotiation", Label(test_constants.LabelUnit, test_constants.LabelHandlers, test_constants.LabelMiddleware), func() {
+	var router *gin.Engine
+
+	sample := types.AgenticSession{
+		APIVersion: "vteam.ambient-code/v1alpha1",
+		Kind:       "AgenticSession",
+		Metadata:   map[string]interface{}{"name": "session-1", "namespace": "tenant-a"},
+		Spec: types.AgenticSessionSpec{
+			DisplayName:   "Fix the flaky test",
+			InitialPrompt: "Investigate TestFoo",
+			Timeout:       300,
+		},
+		Status: &types.AgenticSessionStatus{Phase: SessionPhaseRunning},
+	}
+
+	BeforeEach(func() {
+		gin.SetMode(gin.TestMode)
+		router = gin.New()
+		router.Use(ContentNegotiationMiddleware())
+		router.GET("/session", func(c *gin.Context) {
+			c.JSON(http.StatusOK, sample)
+		})
+		router.GET("/missing", func(c *gin.Context) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+		})
+		router.GET("/stream", func(c *gin.Context) {
+			c.Data(http.StatusOK, ndjsonContentType, []byte("{\"a\":1}\n"))
+		})
+	})
+
+	get := func(path, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	It("Should return JSON by default", func() {
+		rec := get("/session", "")
+
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Header().Get("Content-Type")).To(HavePrefix("application/json"))
+		var got types.AgenticSession
+		Expect(json.Unmarshal(rec.Body.Bytes(), &got)).To(Succeed())
+		Expect(got).To(Equal(sample))
+	})
+
+	It("Should return the same session as YAML when asked", func() {
+		rec := get("/session", YAMLContentType)
+
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Header().Get("Content-Type")).To(HavePrefix(YAMLContentType))
+		Expect(rec.Body.String()).To(ContainSubstring("displayName: Fix the flaky test"))
+		Expect(json.Valid(rec.Body.Bytes())).To(BeFalse(), "body should be YAML, not JSON")
+
+		var got types.AgenticSession
+		Expect(yaml.Unmarshal(rec.Body.Bytes(), &got)).To(Succeed())
+		Expect(got).To(Equal(sample))
+	})
+
+	It("Should encode error responses as YAML with the original status", func() {
+		rec := get("/missing", "application/x-yaml")
+
+		Expect(rec.Code).To(Equal(http.StatusNotFound))
+		Expect(rec.Header().Get("Content-Type")).To(HavePrefix(YAMLContentType))
+		Expect(rec.Body.String()).To(Equal("error: Session not found\n"))
+	})
+
+	DescribeTable("Should pick the first supported type in Accept",
+		func(accept, wantPrefix string) {
+			Expect(get("/session", accept).Header().Get("Content-Type")).To(HavePrefix(wantPrefix))
+		},
+		Entry("YAML listed first", "application/yaml, application/json", YAMLContentType),
+		Entry("JSON listed first", "application/json, application/yaml", "application/json"),
+		Entry("text/yaml with parameters", "text/html, text/yaml; q=0.9", YAMLContentType),
+		Entry("wildcard", "*/*", "application/json"),
+	)
+
+	It("Should leave non-JSON responses untouched", func() {
+		rec := get("/stream", YAMLContentType)
+
+		Expect(rec.Header().Get("Content-Type")).To(Equal(ndjsonContentType))
+		Expect(rec.Body.String()).To(Equal("{\"a\":1}\n"))
+	})
+})
@@ -15,6 +15,8 @@ import (
 	"strings"
 	"time"
 
+	"ambient-code-backend/httpclient"
+
 	"github.com/gin-gonic/gin"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -112,7 +114,7 @@ func doGitHubRequest(ctx context.Context, method string, url string, authHeader
 			req.Header.Set("If-None-Match", s)
 		}
 	}
-	client := &http.Client{Timeout: 15 * time.Second}
+	client := httpclient.NewClient(httpclient.Options{Timeout: 15 * time.Second})
 	return client.Do(req)
 }
 
@@ -246,7 +248,7 @@ func exchangeOAuthCodeForUserToken(clientID, clientSecret, code string) (string,
 	req, _ := http.NewRequest(http.MethodPost, "https://github.com/login/oauth/access_token", reqBody)
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := httpclient.NewClient(httpclient.Options{}).Do(req)
 	if err != nil {
 		return "", err
 	}
@@ -268,7 +270,7 @@ func userOwnsInstallation(userToken string, installationID int64) (bool, string,
 	req.Header.Set("Accept", "application/vnd.github+json")
 	req.Header.Set("Authorization", "token "+userToken)
 	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := httpclient.NewClient(httpclient.Options{}).Do(req)
 	if err != nil {
 		return false, "", err
 	}
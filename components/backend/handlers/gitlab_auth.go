@@ -116,6 +116,13 @@ func (h *GitLabAuthHandler) ConnectGitLab(c *gin.Context) {
 		})
 		return
 	}
+	if err := ValidateNamespace(project); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      err.Error(),
+			"statusCode": http.StatusBadRequest,
+		})
+		return
+	}
 
 	var req ConnectGitLabRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -211,6 +218,13 @@ func (h *GitLabAuthHandler) GetGitLabStatus(c *gin.Context) {
 		})
 		return
 	}
+	if err := ValidateNamespace(project); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      err.Error(),
+			"statusCode": http.StatusBadRequest,
+		})
+		return
+	}
 
 	// Get user ID from context
 	userID, exists := c.Get("userID")
@@ -288,6 +302,13 @@ func (h *GitLabAuthHandler) DisconnectGitLab(c *gin.Context) {
 		})
 		return
 	}
+	if err := ValidateNamespace(project); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      err.Error(),
+			"statusCode": http.StatusBadRequest,
+		})
+		return
+	}
 
 	// Get user ID from context
 	userID, exists := c.Get("userID")
@@ -355,6 +376,10 @@ func ConnectGitLabGlobal(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Project name is required"})
 		return
 	}
+	if err := ValidateNamespace(project); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	// Get user-scoped K8s client (RBAC enforcement)
 	k8sClt, _ := GetK8sClientsForRequest(c)
@@ -377,6 +402,10 @@ func GetGitLabStatusGlobal(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Project name is required"})
 		return
 	}
+	if err := ValidateNamespace(project); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	// Get user-scoped K8s client (RBAC enforcement)
 	k8sClt, _ := GetK8sClientsForRequest(c)
@@ -399,6 +428,10 @@ func DisconnectGitLabGlobal(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Project name is required"})
 		return
 	}
+	if err := ValidateNamespace(project); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	// Get user-scoped K8s client (RBAC enforcement)
 	k8sClt, _ := GetK8sClientsForRequest(c)
@@ -1,12 +1,125 @@
 package handlers
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
 )
 
+// DefaultCRDCheckInterval is how often CRDChecker.Run refreshes its discovery result
+const DefaultCRDCheckInterval = time.Minute
+
+// ProjectSettingsCRDCheck backs the readiness endpoint; nil skips the CRD check
+// Set at startup in main.go
+var ProjectSettingsCRDCheck *CRDChecker
+
 // Health returns a simple health check handler
 func Health(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"status": "healthy"})
 }
+
+// Ready reports whether the backend can serve requests
+// Returns 503 while a required CRD is missing so traffic is held back instead of failing per request
+func Ready(c *gin.Context) {
+	if ProjectSettingsCRDCheck == nil {
+		c.JSON(http.StatusOK, gin.H{"status": "ready"})
+		return
+	}
+	if err := ProjectSettingsCRDCheck.Err(); err != nil {
+		SetRetryAfter(c.Writer, DefaultCRDCheckInterval)
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"status": "not ready",
+			"error":  err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ready"})
+}
+
+// CRDChecker uses API discovery to confirm that a custom resource is served
+// The last result is cached; call Refresh once at startup and Run to keep it current
+type CRDChecker struct {
+	discovery discovery.DiscoveryInterface
+	gvr       schema.GroupVersionResource
+
+	mu      sync.RWMutex
+	checked bool
+	err     error
+}
+
+// NewCRDChecker creates a checker for gvr; nothing is queried until Refresh is called
+func NewCRDChecker(dc discovery.DiscoveryInterface, gvr schema.GroupVersionResource) *CRDChecker {
+	return &CRDChecker{discovery: dc, gvr: gvr}
+}
+
+// Refresh queries discovery and caches the result, returning it
+func (c *CRDChecker) Refresh() error {
+	err := c.lookup()
+	c.mu.Lock()
+	c.checked = true
+	c.err = err
+	c.mu.Unlock()
+	return err
+}
+
+// Err returns the cached result: nil when the resource was found by the last Refresh
+func (c *CRDChecker) Err() error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if !c.checked {
+		return fmt.Errorf("%s: not checked yet", c.resourceName())
+	}
+	return c.err
+}
+
+// Run refreshes the cached result every interval until ctx is cancelled
+// Transitions between available and missing are logged
+func (c *CRDChecker) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			before := c.Err()
+			after := c.Refresh()
+			switch {
+			case before == nil && after != nil:
+				HelperLogger.Printf("CRD check: %v", after)
+			case before != nil && after == nil:
+				HelperLogger.Printf("CRD check: %s is now available", c.resourceName())
+			}
+		}
+	}
+}
+
+// lookup asks discovery whether the group version serves the resource
+func (c *CRDChecker) lookup() error {
+	gv := c.gvr.GroupVersion().String()
+	resources, err := c.discovery.ServerResourcesForGroupVersion(gv)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return fmt.Errorf("%s is not installed (API group version %s not served)", c.resourceName(), gv)
+		}
+		return fmt.Errorf("discovery for %s failed: %w", gv, err)
+	}
+	for _, r := range resources.APIResources {
+		if r.Name == c.gvr.Resource {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s is not installed", c.resourceName())
+}
+
+// resourceName formats the GVR as resource.group/version (e.g. projectsettings.vteam.ambient-code/v1alpha1)
+func (c *CRDChecker) resourceName() string {
+	return fmt.Sprintf("%s.%s/%s", c.gvr.Resource, c.gvr.Group, c.gvr.Version)
+}
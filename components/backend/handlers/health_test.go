@@ -4,6 +4,7 @@ package handlers
 
 import (
 	test_constants "ambient-code-backend/tests/constants"
+	"errors"
 	"net/http"
 	"time"
 
@@ -12,6 +13,10 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	k8stesting "k8s.io/client-go/testing"
 )
 
 var _ = Describe("Health Handler", Label(test_constants.LabelUnit, test_constants.LabelHandlers, test_constants.LabelHealth), func() {
@@ -108,4 +113,84 @@ var _ = Describe("Health Handler", Label(test_constants.LabelUnit, test_constant
 			logger.Log("All concurrent health requests returned 200 OK")
 		})
 	})
+
+	Context("When checking readiness", func() {
+		var discovery *fakediscovery.FakeDiscovery
+
+		BeforeEach(func() {
+			discovery = &fakediscovery.FakeDiscovery{Fake: &k8stesting.Fake{}}
+			DeferCleanup(func() { ProjectSettingsCRDCheck = nil })
+		})
+
+		serveProjectSettings := func() {
+			discovery.Resources = []*v1.APIResourceList{{
+				GroupVersion: "vteam.ambient-code/v1alpha1",
+				APIResources: []v1.APIResource{
+					{Name: "agenticsessions", Kind: "AgenticSession", Namespaced: true},
+					{Name: "projectsettings", Kind: "ProjectSettings", Namespaced: true},
+				},
+			}}
+		}
+
+		It("Should be ready when the ProjectSettings CRD is served", func() {
+			serveProjectSettings()
+			ProjectSettingsCRDCheck = NewCRDChecker(discovery, GetProjectSettingsResource())
+			Expect(ProjectSettingsCRDCheck.Refresh()).To(Succeed())
+
+			Ready(httpUtils.CreateTestGinContext("GET", "/ready", nil))
+
+			httpUtils.AssertHTTPStatus(http.StatusOK)
+			httpUtils.AssertJSONContains(map[string]interface{}{"status": "ready"})
+		})
+
+		It("Should not be ready when the API group is missing", func() {
+			ProjectSettingsCRDCheck = NewCRDChecker(discovery, GetProjectSettingsResource())
+			Expect(ProjectSettingsCRDCheck.Refresh()).To(MatchError(ContainSubstring("projectsettings.vteam.ambient-code/v1alpha1 is not installed")))
+
+			Ready(httpUtils.CreateTestGinContext("GET", "/ready", nil))
+
+			httpUtils.AssertHTTPStatus(http.StatusServiceUnavailable)
+			httpUtils.AssertJSONContains(map[string]interface{}{"status": "not ready"})
+			Expect(httpUtils.GetResponseBody()).To(ContainSubstring("projectsettings.vteam.ambient-code/v1alpha1"))
+			// Retry once the next CRD check may have run
+			Expect(httpUtils.GetResponseRecorder().Header().Get("Retry-After")).To(Equal("60"))
+		})
+
+		It("Should not be ready when the group is served without the resource", func() {
+			discovery.Resources = []*v1.APIResourceList{{
+				GroupVersion: "vteam.ambient-code/v1alpha1",
+				APIResources: []v1.APIResource{{Name: "agenticsessions", Kind: "AgenticSession", Namespaced: true}},
+			}}
+			checker := NewCRDChecker(discovery, GetProjectSettingsResource())
+
+			Expect(checker.Refresh()).To(MatchError("projectsettings.vteam.ambient-code/v1alpha1 is not installed"))
+		})
+
+		It("Should report discovery failures", func() {
+			discovery.PrependReactor("get", "resource", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				return true, nil, errors.New("connection refused")
+			})
+			checker := NewCRDChecker(discovery, GetProjectSettingsResource())
+
+			Expect(checker.Refresh()).To(MatchError(ContainSubstring("connection refused")))
+		})
+
+		It("Should serve the cached result until refreshed", func() {
+			checker := NewCRDChecker(discovery, GetProjectSettingsResource())
+			Expect(checker.Err()).To(MatchError(ContainSubstring("not checked yet")))
+
+			Expect(checker.Refresh()).NotTo(Succeed())
+			serveProjectSettings()
+			Expect(checker.Err()).To(HaveOccurred(), "the CRD appearing is not seen until the next refresh")
+
+			Expect(checker.Refresh()).To(Succeed())
+			Expect(checker.Err()).To(Succeed())
+		})
+
+		It("Should be ready when no CRD check is configured", func() {
+			Ready(httpUtils.CreateTestGinContext("GET", "/ready", nil))
+
+			httpUtils.AssertHTTPStatus(http.StatusOK)
+		})
+	})
 })
@@ -2,11 +2,15 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
-	"math"
+	"slices"
+	"sort"
+	"strings"
 	"time"
 
+	"ambient-code-backend/types"
+
 	authv1 "k8s.io/api/authorization/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -25,27 +29,25 @@ func GetProjectSettingsResource() schema.GroupVersionResource {
 // RetryWithBackoff attempts an operation with exponential backoff
 // Used for operations that may temporarily fail due to async resource creation
 // This is a generic utility that can be used by any handler
-// Checks for context cancellation between retries to avoid wasting resources
+// See RetryWithBackoffContext for a variant that honors cancellation and request IDs
 func RetryWithBackoff(maxRetries int, initialDelay, maxDelay time.Duration, operation func() error) error {
-	var lastErr error
-	for i := 0; i < maxRetries; i++ {
-		if err := operation(); err != nil {
-			lastErr = err
-			if i < maxRetries-1 {
-				// Calculate exponential backoff delay
-				delay := time.Duration(float64(initialDelay) * math.Pow(2, float64(i)))
-				if delay > maxDelay {
-					delay = maxDelay
-				}
-				log.Printf("Operation failed (attempt %d/%d), retrying in %v: %v", i+1, maxRetries, delay, err)
-				time.Sleep(delay)
-				continue
-			}
-		} else {
-			return nil
-		}
-	}
-	return fmt.Errorf("operation failed after %d retries: %w", maxRetries, lastErr)
+	return RetryWithBackoffContext(context.Background(), maxRetries, initialDelay, maxDelay, operation)
+}
+
+// RetryWithBackoffContext is RetryWithBackoff bound to a context
+// Checks for context cancellation between retries to avoid wasting resources
+// Retry log lines include the request ID when one is present in ctx
+func RetryWithBackoffContext(ctx context.Context, maxRetries int, initialDelay, maxDelay time.Duration, operation func() error) error {
+	return RetryWithStrategy(ctx, maxRetries, ExponentialBackoff{Initial: initialDelay, Max: maxDelay}, operation)
+}
+
+// RetryWithStrategy is RetryWithBackoffContext with a caller-chosen backoff curve
+// e.g. ConstantBackoff for polling, LinearBackoff for gently increasing waits
+func RetryWithStrategy(ctx context.Context, maxRetries int, backoff BackoffStrategy, operation func() error) error {
+	return RetryWithOptions(ctx, RetryOptions{
+		MaxRetries: maxRetries,
+		Backoff:    backoff,
+	}, operation)
 }
 
 // ComputeAutoBranch generates the auto-branch name from a session name
@@ -56,29 +58,202 @@ func ComputeAutoBranch(sessionName string) string {
 	return fmt.Sprintf("ambient/%s", sessionName)
 }
 
-// ValidateSecretAccess checks if the user has permission to perform the given verb on secrets
-// Returns an error if the user lacks the required permission
-// Accepts kubernetes.Interface for compatibility with dependency injection in tests
-func ValidateSecretAccess(ctx context.Context, k8sClient kubernetes.Interface, namespace, verb string) error {
+// AccessDeniedError reports that the user lacks permission for a verb on one or more objects
+// Names is empty when the check covered the whole resource collection
+type AccessDeniedError struct {
+	Verb      string
+	Resource  string
+	Names     []string
+	Namespace string
+}
+
+func (e *AccessDeniedError) Error() string {
+	if len(e.Names) == 0 {
+		return fmt.Sprintf("user not allowed to %s %s in namespace %s", e.Verb, e.Resource, e.Namespace)
+	}
+	return fmt.Sprintf("user not allowed to %s %s %s in namespace %s", e.Verb, e.Resource, strings.Join(e.Names, ", "), e.Namespace)
+}
+
+// ValidateResourceAccess checks if the user has permission to perform verb on a resource
+// name narrows the check to a single object; "" checks the whole collection
+// Returns an *AccessDeniedError when the review denies access
+// An empty namespace falls back to the one on ctx (see WithNamespace); with neither, it errors
+// Traced as a SpanValidateResourceAccess span; a denial is recorded as allowed=false, not as a span error
+func ValidateResourceAccess(ctx context.Context, k8sClient kubernetes.Interface, namespace, group, resource, name, verb string) error {
+	allowed, _, namespace, err := checkResourceAccess(ctx, k8sClient, namespace, group, resource, name, verb)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		denied := &AccessDeniedError{Verb: verb, Resource: resource, Namespace: namespace}
+		target := resource
+		if name != "" {
+			denied.Names = []string{name}
+			target = resource + "/" + name
+		}
+		HelperLogger.Printf("%sRBAC: denied %s on %s in namespace %s", requestIDLogPrefix(ctx), verb, target, namespace)
+		return denied
+	}
+	return nil
+}
+
+// checkResourceAccess runs the SelfSubjectAccessReview behind ValidateResourceAccess and CheckSecretAccess
+// It reports a denial through allowed rather than an error and also returns the namespace it resolved
+func checkResourceAccess(ctx context.Context, k8sClient kubernetes.Interface, namespace, group, resource, name, verb string) (allowed bool, reason, resolved string, err error) {
+	ctx, span := startSpan(ctx, SpanValidateResourceAccess, AttrRBACVerb.String(verb), AttrRBACResource.String(resource), AttrRBACName.String(name))
+	defer func() { endSpan(span, err) }()
+
+	resolved, err = resolveNamespace(ctx, namespace)
+	if err != nil {
+		return false, "", "", err
+	}
+	span.SetAttributes(AttrNamespace.String(resolved))
+
 	ssar := &authv1.SelfSubjectAccessReview{
 		Spec: authv1.SelfSubjectAccessReviewSpec{
 			ResourceAttributes: &authv1.ResourceAttributes{
-				Group:     "", // core API group for secrets
-				Resource:  "secrets",
+				Group:     group,
+				Resource:  resource,
+				Name:      name,
 				Verb:      verb, // "create", "get", "update", "delete"
-				Namespace: namespace,
+				Namespace: resolved,
 			},
 		},
 	}
 
 	res, err := k8sClient.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, ssar, v1.CreateOptions{})
 	if err != nil {
-		return fmt.Errorf("RBAC check failed: %w", err)
+		return false, "", resolved, fmt.Errorf("RBAC check failed: %w", err)
 	}
 
-	if !res.Status.Allowed {
-		return fmt.Errorf("user not allowed to %s secrets in namespace %s", verb, namespace)
+	span.SetAttributes(AttrRBACAllowed.Bool(res.Status.Allowed))
+	return res.Status.Allowed, res.Status.Reason, resolved, nil
+}
+
+// CheckSecretAccess reports whether the user may perform verb on secrets without treating a denial as an error
+// reason is the authorizer's explanation and is often empty; err is reserved for failed checks
+// (no namespace, API errors), so callers can branch on allowed, e.g. to show or hide UI
+// An empty namespace falls back to the one on ctx (see WithNamespace)
+func CheckSecretAccess(ctx context.Context, k8sClient kubernetes.Interface, namespace, verb string) (allowed bool, reason string, err error) {
+	allowed, reason, _, err = checkResourceAccess(ctx, k8sClient, namespace, "", "secrets", "", verb)
+	return allowed, reason, err
+}
+
+// CompareAccess reports whether the user may perform verb on gvr's resource in nsA and in nsB,
+// for showing side by side why an action works in one namespace but not the other
+// Denials are reported through the booleans; err is set only when a review could not be performed
+// Both namespaces are required. Comparing two namespaces is the point, so unlike ValidateResourceAccess
+// they are not checked against the authorized namespace on ctx (see WithNamespace)
+func CompareAccess(ctx context.Context, k8sClient kubernetes.Interface, nsA, nsB string, gvr schema.GroupVersionResource, verb string) (allowedA, allowedB bool, err error) {
+	ctx = WithNamespace(ctx, "")
+	allowedA, _, _, err = checkResourceAccess(ctx, k8sClient, nsA, gvr.Group, gvr.Resource, "", verb)
+	if err != nil {
+		return false, false, fmt.Errorf("namespace %s: %w", nsA, err)
 	}
+	allowedB, _, _, err = checkResourceAccess(ctx, k8sClient, nsB, gvr.Group, gvr.Resource, "", verb)
+	if err != nil {
+		return false, false, fmt.Errorf("namespace %s: %w", nsB, err)
+	}
+	return allowedA, allowedB, nil
+}
+
+// ErrRulesIncomplete is returned by ListAllowedVerbs when the rules review may be missing rules,
+// e.g. because an authorizer other than RBAC is in use or a role could not be evaluated
+var ErrRulesIncomplete = errors.New("rules review is incomplete")
+
+// allKubeVerbs is what a "*" verb in a rule expands to
+var allKubeVerbs = []string{"create", "delete", "deletecollection", "get", "list", "patch", "update", "watch"}
+
+// ListAllowedVerbs returns the sorted verbs the user may perform on every object of resource
+// in namespace, from a single SelfSubjectRulesReview instead of one access review per verb
+// Rules limited to specific resourceNames are skipped since they do not cover the whole resource,
+// and a "*" verb expands to the standard Kubernetes verbs
+// When the review reports itself incomplete or an evaluation error, the verbs found so far are returned together with an
+// error wrapping ErrRulesIncomplete; callers can show them and confirm a missing verb with
+// ValidateResourceAccess. An empty namespace falls back to the one on ctx (see WithNamespace)
+func ListAllowedVerbs(ctx context.Context, k8sClient kubernetes.Interface, namespace, resource string) ([]string, error) {
+	resolved, err := resolveNamespace(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	ssrr := &authv1.SelfSubjectRulesReview{
+		Spec: authv1.SelfSubjectRulesReviewSpec{Namespace: resolved},
+	}
+	res, err := k8sClient.AuthorizationV1().SelfSubjectRulesReviews().Create(ctx, ssrr, v1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("rules review failed: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	for _, rule := range res.Status.ResourceRules {
+		if len(rule.ResourceNames) > 0 || !(slices.Contains(rule.Resources, resource) || slices.Contains(rule.Resources, "*")) {
+			continue
+		}
+		for _, verb := range rule.Verbs {
+			if verb == "*" {
+				for _, v := range allKubeVerbs {
+					seen[v] = true
+				}
+				continue
+			}
+			seen[verb] = true
+		}
+	}
+	verbs := make([]string, 0, len(seen))
+	for verb := range seen {
+		verbs = append(verbs, verb)
+	}
+	sort.Strings(verbs)
+
+	// An evaluation error means some rules were dropped even if Incomplete is not set
+	if res.Status.Incomplete || res.Status.EvaluationError != "" {
+		if res.Status.EvaluationError != "" {
+			return verbs, fmt.Errorf("%w in namespace %s: %s", ErrRulesIncomplete, resolved, res.Status.EvaluationError)
+		}
+		return verbs, fmt.Errorf("%w in namespace %s", ErrRulesIncomplete, resolved)
+	}
+	return verbs, nil
+}
+
+// ValidateSecretAccess checks if the user has permission to perform the given verb on secrets
+// Returns an *AccessDeniedError if the user lacks the required permission; see CheckSecretAccess
+// for a non-error result
+// An empty namespace falls back to the one on ctx (see WithNamespace); with neither, it errors
+// Accepts kubernetes.Interface for compatibility with dependency injection in tests
+func ValidateSecretAccess(ctx context.Context, k8sClient kubernetes.Interface, namespace, verb string) error {
+	return ValidateResourceAccess(ctx, k8sClient, namespace, "", "secrets", "", verb)
+}
+
+// ValidateSecretRefsAccess preflights access to every secret referenced by refs
+// Each secret is checked once even if several refs (e.g. different keys) point at it
+// All denials are reported together in one *AccessDeniedError; other failures abort the check
+func ValidateSecretRefsAccess(ctx context.Context, k8sClient kubernetes.Interface, namespace string, refs []types.SecretRef, verb string) error {
+	seen := make(map[string]bool, len(refs))
+	var denied *AccessDeniedError
 
+	for _, ref := range refs {
+		if ref.Name == "" || seen[ref.Name] {
+			continue
+		}
+		seen[ref.Name] = true
+
+		err := ValidateResourceAccess(ctx, k8sClient, namespace, "", "secrets", ref.Name, verb)
+		var accessErr *AccessDeniedError
+		switch {
+		case err == nil:
+		case errors.As(err, &accessErr):
+			if denied == nil {
+				denied = &AccessDeniedError{Verb: verb, Resource: "secrets", Namespace: accessErr.Namespace}
+			}
+			denied.Names = append(denied.Names, ref.Name)
+		default:
+			return fmt.Errorf("failed to check access to secret %s: %w", ref.Name, err)
+		}
+	}
+
+	if denied != nil {
+		return denied
+	}
 	return nil
 }
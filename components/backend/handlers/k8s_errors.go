@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+// APIErrorClass is a coarse classification of a Kubernetes API error
+// Handlers switch on it to pick an HTTP status and decide whether to retry
+type APIErrorClass int
+
+const (
+	// APIErrorNone means there was no error
+	APIErrorNone APIErrorClass = iota
+	// APIErrorNotFound means the resource does not exist (yet)
+	APIErrorNotFound
+	// APIErrorAlreadyExists means a create collided with an existing resource
+	APIErrorAlreadyExists
+	// APIErrorConflict means an update lost an optimistic concurrency race
+	APIErrorConflict
+	// APIErrorUnauthorized means the caller's credentials were rejected
+	APIErrorUnauthorized
+	// APIErrorForbidden means the caller lacks RBAC permission
+	APIErrorForbidden
+	// APIErrorInvalid means the request was rejected by validation
+	APIErrorInvalid
+	// APIErrorTransient means the API server was overloaded, timed out or failed internally
+	APIErrorTransient
+	// APIErrorUnknown covers anything not recognized as a Kubernetes status error
+	APIErrorUnknown
+)
+
+// String returns a short name for the class, suitable for logs
+func (c APIErrorClass) String() string {
+	switch c {
+	case APIErrorNone:
+		return "none"
+	case APIErrorNotFound:
+		return "not_found"
+	case APIErrorAlreadyExists:
+		return "already_exists"
+	case APIErrorConflict:
+		return "conflict"
+	case APIErrorUnauthorized:
+		return "unauthorized"
+	case APIErrorForbidden:
+		return "forbidden"
+	case APIErrorInvalid:
+		return "invalid"
+	case APIErrorTransient:
+		return "transient"
+	default:
+		return "unknown"
+	}
+}
+
+// ClassifyAPIError maps an error from a Kubernetes client call to an APIErrorClass
+// Wrapped errors are unwrapped by the underlying k8s errors helpers
+func ClassifyAPIError(err error) APIErrorClass {
+	switch {
+	case err == nil:
+		return APIErrorNone
+	case errors.IsNotFound(err):
+		return APIErrorNotFound
+	case errors.IsAlreadyExists(err):
+		return APIErrorAlreadyExists
+	case errors.IsConflict(err):
+		return APIErrorConflict
+	case errors.IsUnauthorized(err):
+		return APIErrorUnauthorized
+	case errors.IsForbidden(err):
+		return APIErrorForbidden
+	case errors.IsInvalid(err), errors.IsBadRequest(err):
+		return APIErrorInvalid
+	case errors.IsServerTimeout(err), errors.IsTimeout(err), errors.IsTooManyRequests(err),
+		errors.IsServiceUnavailable(err), errors.IsInternalError(err):
+		return APIErrorTransient
+	default:
+		return APIErrorUnknown
+	}
+}
+
+// HTTPStatus returns the status a handler should respond with for this class
+func (c APIErrorClass) HTTPStatus() int {
+	switch c {
+	case APIErrorNone:
+		return http.StatusOK
+	case APIErrorNotFound:
+		return http.StatusNotFound
+	case APIErrorAlreadyExists, APIErrorConflict:
+		return http.StatusConflict
+	case APIErrorUnauthorized:
+		return http.StatusUnauthorized
+	case APIErrorForbidden:
+		return http.StatusForbidden
+	case APIErrorInvalid:
+		return http.StatusBadRequest
+	case APIErrorTransient:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// Retryable reports whether an operation failing with this class may succeed on retry
+// NotFound is retryable because resources are often created asynchronously (see RetryWithBackoff usage)
+func (c APIErrorClass) Retryable() bool {
+	switch c {
+	case APIErrorNotFound, APIErrorConflict, APIErrorTransient:
+		return true
+	default:
+		return false
+	}
+}
+
+// safeErrorMessages are the client-facing messages WriteSafeError uses for each class
+// They never include the underlying error, which can name internal resources and API paths
+var safeErrorMessages = map[APIErrorClass]string{
+	APIErrorNotFound:      "Resource not found",
+	APIErrorAlreadyExists: "Resource already exists",
+	APIErrorConflict:      "Resource was modified concurrently; retry the request",
+	APIErrorForbidden:     "Permission denied",
+	APIErrorTransient:     "Service temporarily unavailable; retry the request",
+}
+
+// WriteSafeError writes a client-safe JSON error for err and logs the full error server-side
+// NotFound is a 404, AlreadyExists and Conflict a 409, Forbidden a 403 and transient errors a 503
+// with Retry-After (the API server's suggested delay, if any); anything else is a generic 500
+// The log line carries the request ID RequestIDMiddleware set on w, so a client reporting the
+// X-Request-ID header can be matched to the detailed error
+func WriteSafeError(w http.ResponseWriter, err error) {
+	class := ClassifyAPIError(err)
+	status, message := http.StatusInternalServerError, "Internal server error"
+	if safe, ok := safeErrorMessages[class]; ok {
+		status, message = class.HTTPStatus(), safe
+	}
+
+	prefix := ""
+	if id := w.Header().Get(RequestIDHeader); id != "" {
+		prefix = "[req=" + id + "] "
+	}
+	log.Printf("%sRequest failed with %d (%s): %v", prefix, status, class, err)
+	if status == http.StatusServiceUnavailable {
+		SetRetryAfter(w, apiErrorRetryAfter(err))
+	}
+	writeJSON(w, status, map[string]string{"error": message})
+}
@@ -0,0 +1,112 @@
+//go:build test
+
+package handlers
+
+import (
+	test_constants "ambient-code-backend/tests/constants"
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var _ = Describe("Kubernetes API Error Classification", Label(test_constants.LabelUnit, test_constants.LabelHandlers), func() {
+	gr := schema.GroupResource{Group: "vteam.ambient-code", Resource: "agenticsessions"}
+
+	DescribeTable("ClassifyAPIError",
+		func(err error, want APIErrorClass, status int, retryable bool) {
+			class := ClassifyAPIError(err)
+			Expect(class).To(Equal(want))
+			Expect(class.HTTPStatus()).To(Equal(status))
+			Expect(class.Retryable()).To(Equal(retryable))
+		},
+		Entry("nil", nil, APIErrorNone, http.StatusOK, false),
+		Entry("NotFound", k8serrors.NewNotFound(gr, "s1"), APIErrorNotFound, http.StatusNotFound, true),
+		Entry("AlreadyExists", k8serrors.NewAlreadyExists(gr, "s1"), APIErrorAlreadyExists, http.StatusConflict, false),
+		Entry("Conflict", k8serrors.NewConflict(gr, "s1", errors.New("modified")), APIErrorConflict, http.StatusConflict, true),
+		Entry("Unauthorized", k8serrors.NewUnauthorized("bad token"), APIErrorUnauthorized, http.StatusUnauthorized, false),
+		Entry("Forbidden", k8serrors.NewForbidden(gr, "s1", errors.New("denied")), APIErrorForbidden, http.StatusForbidden, false),
+		Entry("BadRequest", k8serrors.NewBadRequest("bad"), APIErrorInvalid, http.StatusBadRequest, false),
+		Entry("ServerTimeout", k8serrors.NewServerTimeout(gr, "get", 1), APIErrorTransient, http.StatusServiceUnavailable, true),
+		Entry("TooManyRequests", k8serrors.NewTooManyRequests("slow down", 1), APIErrorTransient, http.StatusServiceUnavailable, true),
+		Entry("ServiceUnavailable", k8serrors.NewServiceUnavailable("down"), APIErrorTransient, http.StatusServiceUnavailable, true),
+		Entry("InternalError", k8serrors.NewInternalError(errors.New("boom")), APIErrorTransient, http.StatusServiceUnavailable, true),
+		Entry("wrapped NotFound", fmt.Errorf("get: %w", k8serrors.NewNotFound(gr, "s1")), APIErrorNotFound, http.StatusNotFound, true),
+		Entry("plain error", errors.New("boom"), APIErrorUnknown, http.StatusInternalServerError, false),
+	)
+
+	It("Should give each class a readable name", func() {
+		Expect(APIErrorForbidden.String()).To(Equal("forbidden"))
+		Expect(APIErrorUnknown.String()).To(Equal("unknown"))
+	})
+
+	Describe("WriteSafeError", func() {
+		var logBuf *bytes.Buffer
+
+		BeforeEach(func() {
+			logBuf = &bytes.Buffer{}
+			log.SetOutput(logBuf)
+		})
+
+		AfterEach(func() {
+			log.SetOutput(os.Stderr)
+		})
+
+		DescribeTable("Should map classified errors to generic messages",
+			func(err error, status int, message string) {
+				recorder := httptest.NewRecorder()
+				WriteSafeError(recorder, err)
+
+				Expect(recorder.Code).To(Equal(status))
+				Expect(recorder.Body.String()).To(MatchJSON(fmt.Sprintf(`{"error": %q}`, message)))
+				Expect(recorder.Body.String()).NotTo(ContainSubstring("agenticsessions"))
+				Expect(recorder.Body.String()).NotTo(ContainSubstring("secret-s1"))
+			},
+			Entry("NotFound", k8serrors.NewNotFound(gr, "secret-s1"), http.StatusNotFound, "Resource not found"),
+			Entry("AlreadyExists", k8serrors.NewAlreadyExists(gr, "secret-s1"), http.StatusConflict, "Resource already exists"),
+			Entry("Conflict", k8serrors.NewConflict(gr, "secret-s1", errors.New("modified")), http.StatusConflict, "Resource was modified concurrently; retry the request"),
+			Entry("Forbidden", k8serrors.NewForbidden(gr, "secret-s1", errors.New("denied")), http.StatusForbidden, "Permission denied"),
+			Entry("transient", k8serrors.NewServerTimeout(gr, "get", 1), http.StatusServiceUnavailable, "Service temporarily unavailable; retry the request"),
+			Entry("Unauthorized", k8serrors.NewUnauthorized("token for secret-s1 rejected"), http.StatusInternalServerError, "Internal server error"),
+			Entry("Invalid", k8serrors.NewBadRequest("agenticsessions secret-s1 is invalid"), http.StatusInternalServerError, "Internal server error"),
+			Entry("plain error", errors.New("GET /apis/vteam.ambient-code/v1alpha1/agenticsessions/secret-s1 failed"), http.StatusInternalServerError, "Internal server error"),
+		)
+
+		DescribeTable("Should set Retry-After only on a 503",
+			func(err error, retryAfter string) {
+				recorder := httptest.NewRecorder()
+				WriteSafeError(recorder, err)
+				Expect(recorder.Header().Get(RetryAfterHeader)).To(Equal(retryAfter))
+			},
+			Entry("throttled with a suggested delay", k8serrors.NewTooManyRequests("slow down", 7), "7"),
+			Entry("unavailable without a suggestion", k8serrors.NewServiceUnavailable("down"), "5"),
+			Entry("NotFound", k8serrors.NewNotFound(gr, "s1"), ""),
+			Entry("plain error", errors.New("boom"), ""),
+		)
+
+		It("Should log the full error with the request ID", func() {
+			recorder := httptest.NewRecorder()
+			recorder.Header().Set(RequestIDHeader, "safe-req-1")
+			WriteSafeError(recorder, fmt.Errorf("get session: %w", k8serrors.NewNotFound(gr, "secret-s1")))
+
+			Expect(recorder.Code).To(Equal(http.StatusNotFound))
+			Expect(recorder.Body.String()).NotTo(ContainSubstring("secret-s1"))
+			Expect(logBuf.String()).To(ContainSubstring("[req=safe-req-1] Request failed with 404 (not_found)"))
+			Expect(logBuf.String()).To(ContainSubstring(`agenticsessions.vteam.ambient-code "secret-s1" not found`))
+		})
+
+		It("Should log without a prefix when there is no request ID", func() {
+			WriteSafeError(httptest.NewRecorder(), errors.New("boom"))
+			Expect(logBuf.String()).To(ContainSubstring("Request failed with 500 (unknown): boom"))
+			Expect(logBuf.String()).NotTo(ContainSubstring("[req="))
+		})
+	})
+})
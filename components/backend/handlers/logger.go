@@ -0,0 +1,28 @@
+package handlers
+
+import "log"
+
+// Logger receives log lines from retry and the other shared helpers in this package
+// Assign HelperLogger to route them to a structured logger (zap, logr) or to capture them in tests
+type Logger interface {
+	Printf(format string, args ...interface{})
+}
+
+// StdLogger adapts a standard library logger to Logger
+// The zero value writes through the log package's default logger
+type StdLogger struct {
+	L *log.Logger
+}
+
+// Printf logs to L, or to the standard logger when L is nil
+func (s StdLogger) Printf(format string, args ...interface{}) {
+	if s.L == nil {
+		log.Printf(format, args...)
+		return
+	}
+	s.L.Printf(format, args...)
+}
+
+// HelperLogger is the Logger used by RetryWithOptions, ValidateResourceAccess,
+// SessionStatusClient and CRDChecker; it must not be nil
+var HelperLogger Logger = StdLogger{}
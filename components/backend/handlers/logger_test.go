@@ -0,0 +1,72 @@
+//go:build test
+
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	test_constants "ambient-code-backend/tests/constants"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// fakeLogger records formatted lines instead of writing them anywhere
+type fakeLogger struct {
+	lines []string
+}
+
+func (f *fakeLogger) Printf(format string, args ...interface{}) {
+	f.lines = append(f.lines, fmt.Sprintf(format, args...))
+}
+
+var _ = Describe("Logger", Label(test_constants.LabelUnit, test_constants.LabelHandlers), func() {
+	var fake *fakeLogger
+
+	BeforeEach(func() {
+		fake = &fakeLogger{}
+		previous := HelperLogger
+		HelperLogger = fake
+		DeferCleanup(func() { HelperLogger = previous })
+	})
+
+	It("Should route retry messages to the injected logger", func() {
+		attempts := 0
+		err := RetryWithOptions(WithRequestID(context.Background(), "req-7"), RetryOptions{
+			MaxRetries: 3,
+			Operation:  "update-project",
+			Backoff:    ConstantBackoff{Interval: time.Millisecond},
+		}, func() error {
+			attempts++
+			if attempts < 3 {
+				return errors.New("conflict")
+			}
+			return nil
+		})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fake.lines).To(Equal([]string{
+			"[req=req-7] Operation update-project failed (attempt 1/3), retrying in 1ms: conflict",
+			"[req=req-7] Operation update-project failed (attempt 2/3), retrying in 1ms: conflict",
+		}))
+	})
+
+	It("Should log nothing when the first attempt succeeds", func() {
+		Expect(RetryWithBackoff(3, time.Millisecond, time.Millisecond, func() error { return nil })).To(Succeed())
+		Expect(fake.lines).To(BeEmpty())
+	})
+
+	Describe("StdLogger", func() {
+		It("Should write to the wrapped logger", func() {
+			var buf bytes.Buffer
+			StdLogger{L: log.New(&buf, "backend: ", 0)}.Printf("retrying %s", "update")
+
+			Expect(buf.String()).To(Equal("backend: retrying update\n"))
+		})
+	})
+})
@@ -328,8 +328,8 @@ func ValidateProjectContext() gin.HandlerFunc {
 		}
 
 		// Validate namespace name to prevent injection attacks
-		if !isValidKubernetesName(projectHeader) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project name format"})
+		if err := ValidateNamespace(projectHeader); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			c.Abort()
 			return
 		}
@@ -360,6 +360,7 @@ func ValidateProjectContext() gin.HandlerFunc {
 
 		// Store project in context for handlers
 		c.Set("project", projectHeader)
+		c.Request = c.Request.WithContext(WithNamespace(c.Request.Context(), projectHeader))
 		c.Next()
 	}
 }
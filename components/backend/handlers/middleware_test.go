@@ -151,6 +151,17 @@ var _ = Describe("Middleware Handlers", Label(test_constants.LabelUnit, test_con
 				Expect(context.IsAborted()).To(BeFalse(), "Request with valid auth should not be aborted")
 			})
 
+			It("Should store the authorized project as the request context namespace", func() {
+				context := httpUtils.CreateTestGinContext("GET", "/api/projects/test-project/sessions", nil)
+				context.Params = gin.Params{{Key: "projectName", Value: "test-project"}}
+				httpUtils.SetAuthHeader("valid-test-token")
+
+				middleware(context)
+
+				Expect(context.IsAborted()).To(BeFalse())
+				Expect(NamespaceFromContext(context.Request.Context())).To(Equal("test-project"))
+			})
+
 			It("Should accept token with valid RBAC permissions", func() {
 				context := httpUtils.CreateTestGinContext("GET", "/api/projects/test-project/sessions", nil)
 				context.Params = gin.Params{{Key: "projectName", Value: "test-project"}}
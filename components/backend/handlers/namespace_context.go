@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"k8s.io/client-go/dynamic"
+)
+
+// ErrNamespaceRequired is returned when neither an explicit namespace nor a context namespace is available
+var ErrNamespaceRequired = errors.New("namespace is required")
+
+// ErrInvalidNamespace is returned when a namespace is not a valid DNS-1123 label
+var ErrInvalidNamespace = errors.New("invalid namespace")
+
+// maxNamespaceLength is the DNS-1123 label limit Kubernetes applies to namespace names
+const maxNamespaceLength = 63
+
+// ErrNamespaceMismatch is returned when an explicit namespace disagrees with the authorized context namespace
+var ErrNamespaceMismatch = errors.New("namespace does not match the authorized project")
+
+// ValidateNamespace checks that ns is a usable Kubernetes namespace name (a DNS-1123 label)
+// Handlers call it on namespaces taken from paths, headers or query params so a bad value
+// is answered with a 400 instead of surfacing as an API server error
+func ValidateNamespace(ns string) error {
+	switch {
+	case ns == "":
+		return ErrNamespaceRequired
+	case len(ns) > maxNamespaceLength:
+		return fmt.Errorf("%w: %d characters, must be at most %d", ErrInvalidNamespace, len(ns), maxNamespaceLength)
+	case !kubernetesNameRegex.MatchString(ns):
+		return fmt.Errorf("%w %q: must consist of lowercase alphanumeric characters or '-', and start and end with an alphanumeric character", ErrInvalidNamespace, ns)
+	default:
+		return nil
+	}
+}
+
+type namespaceKey struct{}
+
+// WithNamespace returns a copy of ctx carrying the namespace the caller was authorized for
+// Set by ValidateProjectContext once the project access review passes
+func WithNamespace(ctx context.Context, namespace string) context.Context {
+	return context.WithValue(ctx, namespaceKey{}, namespace)
+}
+
+// NamespaceFromContext returns the namespace stored in ctx, or "" if none
+func NamespaceFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	ns, _ := ctx.Value(namespaceKey{}).(string)
+	return ns
+}
+
+// resolveNamespace picks the namespace for a tenant-scoped operation
+// An explicit namespace must match the context namespace when both are set, so a handler
+// reading the namespace from the wrong source cannot reach another tenant
+func resolveNamespace(ctx context.Context, namespace string) (string, error) {
+	fromCtx := NamespaceFromContext(ctx)
+	switch {
+	case namespace == "" && fromCtx == "":
+		return "", ErrNamespaceRequired
+	case namespace == "":
+		return fromCtx, nil
+	case fromCtx != "" && fromCtx != namespace:
+		return "", fmt.Errorf("%w: got %s, authorized for %s", ErrNamespaceMismatch, namespace, fromCtx)
+	default:
+		return namespace, nil
+	}
+}
+
+// ProjectSettingsClient returns a client for ProjectSettings in the caller's namespace
+// Pass "" to use the namespace from ctx (see WithNamespace)
+func ProjectSettingsClient(ctx context.Context, dyn dynamic.Interface, namespace string) (dynamic.ResourceInterface, error) {
+	ns, err := resolveNamespace(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+	return dyn.Resource(GetProjectSettingsResource()).Namespace(ns), nil
+}
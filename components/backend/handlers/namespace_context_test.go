@@ -0,0 +1,157 @@
+//go:build test
+
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	test_constants "ambient-code-backend/tests/constants"
+	"ambient-code-backend/tests/test_utils"
+
+	"github.com/gin-gonic/gin"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	authv1 "k8s.io/api/authorization/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var _ = Describe("Namespace Context", Label(test_constants.LabelUnit, test_constants.LabelHandlers, test_constants.LabelMiddleware), func() {
+	Describe("WithNamespace", func() {
+		It("Should round-trip the namespace through the context", func() {
+			ctx := WithNamespace(context.Background(), "tenant-a")
+			Expect(NamespaceFromContext(ctx)).To(Equal("tenant-a"))
+			Expect(NamespaceFromContext(context.Background())).To(BeEmpty())
+		})
+	})
+
+	Describe("ValidateNamespace", func() {
+		DescribeTable("Should accept DNS-1123 labels",
+			func(ns string) {
+				Expect(ValidateNamespace(ns)).To(Succeed())
+			},
+			Entry("simple", "tenant-a"),
+			Entry("digits only", "42"),
+			Entry("single character", "a"),
+			Entry("exactly 63 characters", strings.Repeat("a", 63)),
+		)
+
+		It("Should require a namespace", func() {
+			Expect(ValidateNamespace("")).To(MatchError(ErrNamespaceRequired))
+		})
+
+		It("Should reject namespaces longer than 63 characters without echoing them", func() {
+			err := ValidateNamespace(strings.Repeat("a", 64))
+
+			Expect(err).To(MatchError(ErrInvalidNamespace))
+			Expect(err.Error()).To(ContainSubstring("64 characters, must be at most 63"))
+			Expect(err.Error()).NotTo(ContainSubstring(strings.Repeat("a", 64)))
+		})
+
+		DescribeTable("Should reject invalid characters",
+			func(ns string) {
+				err := ValidateNamespace(ns)
+				Expect(err).To(MatchError(ErrInvalidNamespace))
+				Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("%q", ns)))
+			},
+			Entry("uppercase", "Tenant-A"),
+			Entry("underscore", "tenant_a"),
+			Entry("dot", "tenant.a"),
+			Entry("path traversal", "../kube-system"),
+			Entry("leading dash", "-tenant"),
+			Entry("trailing dash", "tenant-"),
+			Entry("whitespace", " tenant"),
+		)
+
+		It("Should make handlers answer 400 instead of calling the API", func() {
+			httpUtils := test_utils.NewHTTPTestUtils()
+			ginContext := httpUtils.CreateTestGinContext("GET", "/api/projects/Bad_Name", nil)
+			ginContext.Params = gin.Params{{Key: "projectName", Value: "Bad_Name"}}
+			httpUtils.SetAuthHeader("test-token")
+
+			GetProject(ginContext)
+
+			httpUtils.AssertHTTPStatus(http.StatusBadRequest)
+			httpUtils.AssertErrorMessage(ValidateNamespace("Bad_Name").Error())
+		})
+	})
+
+	Describe("ValidateSecretAccess", func() {
+		var (
+			k8sClient   *k8sfake.Clientset
+			reviewedNSs []string
+		)
+
+		BeforeEach(func() {
+			reviewedNSs = nil
+			k8sClient = k8sfake.NewSimpleClientset()
+			k8sClient.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				ssar := action.(k8stesting.CreateAction).GetObject().(*authv1.SelfSubjectAccessReview)
+				reviewedNSs = append(reviewedNSs, ssar.Spec.ResourceAttributes.Namespace)
+				ssar.Status.Allowed = true
+				return true, ssar, nil
+			})
+		})
+
+		It("Should use the context namespace when none is passed", func() {
+			ctx := WithNamespace(context.Background(), "tenant-a")
+
+			Expect(ValidateSecretAccess(ctx, k8sClient, "", "get")).To(Succeed())
+			Expect(reviewedNSs).To(Equal([]string{"tenant-a"}))
+		})
+
+		It("Should error when no namespace is available", func() {
+			err := ValidateSecretAccess(context.Background(), k8sClient, "", "get")
+
+			Expect(err).To(MatchError(ErrNamespaceRequired))
+			Expect(reviewedNSs).To(BeEmpty(), "no access review should be attempted")
+		})
+
+		It("Should reject an explicit namespace that differs from the context", func() {
+			ctx := WithNamespace(context.Background(), "tenant-a")
+
+			err := ValidateSecretAccess(ctx, k8sClient, "tenant-b", "get")
+
+			Expect(err).To(MatchError(ErrNamespaceMismatch))
+			Expect(reviewedNSs).To(BeEmpty())
+		})
+
+		It("Should accept an explicit namespace without a context namespace", func() {
+			Expect(ValidateSecretAccess(context.Background(), k8sClient, "tenant-b", "update")).To(Succeed())
+			Expect(reviewedNSs).To(Equal([]string{"tenant-b"}))
+		})
+	})
+
+	Describe("ProjectSettingsClient", func() {
+		var dyn *dynamicfake.FakeDynamicClient
+
+		BeforeEach(func() {
+			dyn = dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+				GetProjectSettingsResource(): "ProjectSettingsList",
+			})
+		})
+
+		It("Should scope requests to the context namespace", func() {
+			client, err := ProjectSettingsClient(WithNamespace(context.Background(), "tenant-a"), dyn, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = client.List(context.Background(), v1.ListOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dyn.Actions()).To(HaveLen(1))
+			Expect(dyn.Actions()[0].GetNamespace()).To(Equal("tenant-a"))
+		})
+
+		It("Should error when no namespace is available", func() {
+			_, err := ProjectSettingsClient(context.Background(), dyn, "")
+			Expect(err).To(MatchError(ErrNamespaceRequired))
+		})
+	})
+})
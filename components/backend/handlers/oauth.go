@@ -14,6 +14,8 @@ import (
 	"strings"
 	"time"
 
+	"ambient-code-backend/httpclient"
+
 	"github.com/gin-gonic/gin"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -412,7 +414,7 @@ func exchangeOAuthCode(ctx context.Context, provider *OAuthProvider, code string
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	req.Header.Set("Accept", "application/json")
 
-	client := &http.Client{Timeout: 15 * time.Second}
+	client := httpclient.NewClient(httpclient.Options{Timeout: 15 * time.Second})
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to exchange code: %w", err)
@@ -1159,7 +1161,7 @@ func getGoogleUserEmail(ctx context.Context, accessToken string) (string, error)
 	req.Header.Set("Authorization", "Bearer "+accessToken)
 
 	// Use client with timeout instead of DefaultClient
-	client := &http.Client{Timeout: 10 * time.Second}
+	client := httpclient.NewClient(httpclient.Options{Timeout: 10 * time.Second})
 	resp, err := client.Do(req)
 	if err != nil {
 		return "", err
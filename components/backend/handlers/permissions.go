@@ -64,6 +64,10 @@ func ListProjectPermissions(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Project name is required"})
 		return
 	}
+	if err := ValidateNamespace(projectName); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	reqK8s, _ := GetK8sClientsForRequest(c)
 	k8sClient := reqK8s
@@ -228,6 +232,10 @@ func RemoveProjectPermission(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Project is required in path /api/projects/:projectName or X-OpenShift-Project header"})
 		return
 	}
+	if err := ValidateNamespace(projectName); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	subjectType := strings.ToLower(c.Param("subjectType"))
 	subjectName := c.Param("subjectName")
 
@@ -344,6 +352,10 @@ func CreateProjectKey(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Project name is required"})
 		return
 	}
+	if err := ValidateNamespace(projectName); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	reqK8s, _ := GetK8sClientsForRequest(c)
 	k8sClient := reqK8s
@@ -451,6 +463,10 @@ func DeleteProjectKey(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Project name is required"})
 		return
 	}
+	if err := ValidateNamespace(projectName); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	keyID := c.Param("keyId")
 	if strings.TrimSpace(keyID) == "" {
@@ -634,6 +634,10 @@ func GetProject(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Project name is required"})
 		return
 	}
+	if err := ValidateNamespace(projectName); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	k8sClt, _ := GetK8sClientsForRequest(c)
 	if k8sClt == nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or missing token"})
@@ -692,6 +696,10 @@ func GetProject(c *gin.Context) {
 // On Kubernetes: No-op (k8s namespaces don't have display metadata)
 func UpdateProject(c *gin.Context) {
 	projectName := c.Param("projectName")
+	if err := ValidateNamespace(projectName); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	k8sClt, _ := GetK8sClientsForRequest(c)
 	if k8sClt == nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or missing token"})
@@ -802,6 +810,10 @@ func DeleteProject(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Project name is required"})
 		return
 	}
+	if err := ValidateNamespace(projectName); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	k8sClt, _ := GetK8sClientsForRequest(c)
 	if k8sClt == nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or missing token"})
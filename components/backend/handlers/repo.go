@@ -168,6 +168,7 @@ func ListUserForks(c *gin.Context) {
 		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 			b, _ := io.ReadAll(resp.Body)
 			_ = resp.Body.Close()
+			ForwardRetryAfter(c.Writer, resp)
 			c.JSON(resp.StatusCode, gin.H{"error": string(b)})
 			return
 		}
@@ -261,6 +262,7 @@ func CreateUserFork(c *gin.Context) {
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusAccepted && (resp.StatusCode < 200 || resp.StatusCode >= 300) {
 		b, _ := io.ReadAll(resp.Body)
+		ForwardRetryAfter(c.Writer, resp)
 		c.JSON(resp.StatusCode, gin.H{"error": string(b)})
 		return
 	}
@@ -356,6 +358,7 @@ func GetRepoTree(c *gin.Context) {
 		defer resp.Body.Close()
 		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 			b, _ := io.ReadAll(resp.Body)
+			ForwardRetryAfter(c.Writer, resp)
 			c.JSON(resp.StatusCode, gin.H{"error": string(b)})
 			return
 		}
@@ -499,6 +502,7 @@ func ListRepoBranches(c *gin.Context) {
 
 		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 			b, _ := io.ReadAll(resp.Body)
+			ForwardRetryAfter(c.Writer, resp)
 			c.JSON(resp.StatusCode, gin.H{"error": string(b)})
 			return
 		}
@@ -618,6 +622,7 @@ func GetRepoBlob(c *gin.Context) {
 		defer resp.Body.Close()
 		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 			b, _ := io.ReadAll(resp.Body)
+			ForwardRetryAfter(c.Writer, resp)
 			c.JSON(resp.StatusCode, gin.H{"error": string(b)})
 			return
 		}
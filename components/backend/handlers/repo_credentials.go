@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"ambient-code-backend/types"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// credentialProviderKey is the label or annotation naming the Git provider a credentials Secret is for
+// Integration secrets created by the backend already carry it as a label
+const credentialProviderKey = "ambient-code.io/provider"
+
+// CheckOutputProvider reports a provider_mismatch error when repo pushes to a host of a different
+// provider than credentialProvider, e.g. a GitHub token used for a GitLab output
+// The output is checked when present, otherwise the input (which is then where pushes go)
+// An empty credentialProvider or a host of unknown provider cannot be judged and passes
+func CheckOutputProvider(repo types.SimpleRepo, credentialProvider types.ProviderType) error {
+	if credentialProvider == "" {
+		return nil
+	}
+	field, target := "output.url", repo.Output
+	if target == nil {
+		input := repo.InputLocation()
+		field, target = "url", &input
+	}
+	hostProvider := types.DetectProvider(target.URL)
+	if hostProvider == "" || hostProvider == credentialProvider {
+		return nil
+	}
+	return newRepoValidationError(field, RepoErrProviderMismatch,
+		"%s is a %s repository but the credentials are for %s", field, hostProvider, credentialProvider)
+}
+
+// ValidateRepoCredentialsProvider reads the Secret behind ref and runs CheckOutputProvider with the
+// provider it is labeled or annotated with (ambient-code.io/provider); the annotation wins if both differ
+// Opt-in: callers run it only when they know which Secret the push will use, and Secrets without
+// the key pass. A missing Secret or an unrecognized provider value is an error
+func ValidateRepoCredentialsProvider(ctx context.Context, k8sClient kubernetes.Interface, namespace string, repo types.SimpleRepo, ref types.SecretRef) error {
+	secret, err := k8sClient.CoreV1().Secrets(namespace).Get(ctx, ref.Name, v1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to read credentials secret %s: %w", ref.Name, err)
+	}
+
+	value := secret.Annotations[credentialProviderKey]
+	if value == "" {
+		value = secret.Labels[credentialProviderKey]
+	}
+	if value == "" {
+		return nil
+	}
+	provider := types.ProviderType(strings.ToLower(strings.TrimSpace(value)))
+	if !provider.IsValid() {
+		return fmt.Errorf("credentials secret %s has unknown provider %q", ref.Name, value)
+	}
+	return CheckOutputProvider(repo, provider)
+}
@@ -0,0 +1,104 @@
+//go:build test
+
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	test_constants "ambient-code-backend/tests/constants"
+	"ambient-code-backend/types"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+var _ = Describe("Repo credentials provider", Label(test_constants.LabelUnit, test_constants.LabelHandlers, test_constants.LabelRepoConfig), func() {
+	toFork := func(outputURL string) types.SimpleRepo {
+		return types.SimpleRepo{
+			URL:    "https://github.com/up/repo.git",
+			Output: &types.RepoLocation{URL: outputURL},
+		}
+	}
+
+	DescribeTable("CheckOutputProvider",
+		func(repo types.SimpleRepo, provider types.ProviderType, wantField string) {
+			err := CheckOutputProvider(repo, provider)
+			if wantField == "" {
+				Expect(err).NotTo(HaveOccurred())
+				return
+			}
+			var verr *RepoValidationError
+			Expect(errors.As(err, &verr)).To(BeTrue())
+			Expect(verr.Field).To(Equal(wantField))
+			Expect(errors.Is(err, ErrRepoProviderMismatch)).To(BeTrue())
+		},
+		Entry("GitHub token, GitHub output", toFork("https://github.com/fork/repo.git"), types.ProviderGitHub, ""),
+		Entry("GitLab token, GitLab output", toFork("https://gitlab.com/fork/repo.git"), types.ProviderGitLab, ""),
+		Entry("GitHub token, GitLab output", toFork("https://gitlab.com/fork/repo.git"), types.ProviderGitHub, "output.url"),
+		Entry("GitLab token, GitHub output", toFork("git@github.com:fork/repo.git"), types.ProviderGitLab, "output.url"),
+		Entry("no output checks the input", types.SimpleRepo{URL: "https://gitlab.com/group/repo.git"}, types.ProviderGitHub, "url"),
+		Entry("unknown output host passes", toFork("https://git.example.org/fork/repo.git"), types.ProviderGitHub, ""),
+		Entry("unannotated credentials pass", toFork("https://gitlab.com/fork/repo.git"), types.ProviderType(""), ""),
+	)
+
+	It("Should name both providers in the message", func() {
+		err := CheckOutputProvider(toFork("https://gitlab.com/fork/repo.git"), types.ProviderGitHub)
+		Expect(err).To(MatchError("output.url is a gitlab repository but the credentials are for github"))
+	})
+
+	Describe("ValidateRepoCredentialsProvider", func() {
+		const namespace = "tenant-a"
+		var k8sClient *k8sfake.Clientset
+
+		secret := func(name string, labels, annotations map[string]string) *corev1.Secret {
+			return &corev1.Secret{ObjectMeta: v1.ObjectMeta{
+				Name: name, Namespace: namespace, Labels: labels, Annotations: annotations,
+			}}
+		}
+
+		BeforeEach(func() {
+			k8sClient = k8sfake.NewSimpleClientset(
+				secret("github-creds", nil, map[string]string{credentialProviderKey: "GitHub"}),
+				secret("gitlab-creds", map[string]string{credentialProviderKey: "gitlab"}, nil),
+				secret("plain-creds", nil, nil),
+				secret("odd-creds", nil, map[string]string{credentialProviderKey: "bitbucket"}),
+			)
+		})
+
+		validate := func(repo types.SimpleRepo, name string) error {
+			return ValidateRepoCredentialsProvider(context.Background(), k8sClient, namespace, repo, types.SecretRef{Name: name, Key: "token"})
+		}
+
+		It("Should accept credentials matching the output host", func() {
+			Expect(validate(toFork("https://github.com/fork/repo.git"), "github-creds")).To(Succeed())
+			Expect(validate(toFork("https://gitlab.com/fork/repo.git"), "gitlab-creds")).To(Succeed())
+		})
+
+		It("Should reject credentials for another provider", func() {
+			err := validate(toFork("https://gitlab.com/fork/repo.git"), "github-creds")
+			Expect(errors.Is(err, ErrRepoProviderMismatch)).To(BeTrue())
+
+			err = validate(toFork("https://github.com/fork/repo.git"), "gitlab-creds")
+			Expect(errors.Is(err, ErrRepoProviderMismatch)).To(BeTrue())
+		})
+
+		It("Should skip secrets without a provider", func() {
+			Expect(validate(toFork("https://gitlab.com/fork/repo.git"), "plain-creds")).To(Succeed())
+		})
+
+		It("Should reject an unknown provider value", func() {
+			err := validate(toFork("https://github.com/fork/repo.git"), "odd-creds")
+			Expect(err).To(MatchError(`credentials secret odd-creds has unknown provider "bitbucket"`))
+		})
+
+		It("Should return an error for a missing secret", func() {
+			err := validate(toFork("https://github.com/fork/repo.git"), "missing")
+			Expect(err).To(MatchError(ContainSubstring("failed to read credentials secret missing")))
+			Expect(errors.Is(err, ErrRepoProviderMismatch)).To(BeFalse())
+		})
+	})
+})
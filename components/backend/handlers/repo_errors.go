@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// WriteRepoError translates a repo parsing error into an HTTP response
+// RepoValidationError becomes a 400 with its field, code and message
+// Any other error becomes a 500 with a generic message; details are only logged
+func WriteRepoError(w http.ResponseWriter, err error) {
+	var verr *RepoValidationError
+	if errors.As(err, &verr) {
+		writeJSON(w, http.StatusBadRequest, verr)
+		return
+	}
+
+	log.Printf("Unexpected error while processing repos: %v", err)
+	writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Internal server error"})
+}
+
+// FieldError is one entry of a validation error response, shaped like a RepoValidationError
+// Field is a path into the request body (e.g. "repos[1].dir" or "sessionTimeout") and Code
+// one of the RepoErr* codes
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ValidationErrors collects every problem found in a request so they can all be returned in
+// one 400, rather than making the caller fix and resubmit one at a time
+type ValidationErrors []FieldError
+
+// Add records err under field; nil is ignored
+// Joined errors (errors.Join) are recorded one by one, a RepoIndexError moves its error under
+// repos[i], and a RepoValidationError keeps its own code and extends field with its own
+// Any other error is recorded as is with code
+func (v *ValidationErrors) Add(field, code string, err error) {
+	if err == nil {
+		return
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range joined.Unwrap() {
+			v.Add(field, code, e)
+		}
+		return
+	}
+	var ierr *RepoIndexError
+	if errors.As(err, &ierr) {
+		v.Add(joinFieldPath(field, fmt.Sprintf("repos[%d]", ierr.Index)), code, ierr.Err)
+		return
+	}
+	var verr *RepoValidationError
+	if errors.As(err, &verr) {
+		*v = append(*v, FieldError{Field: joinFieldPath(field, verr.Field), Code: verr.Code, Message: verr.Message})
+		return
+	}
+	*v = append(*v, FieldError{Field: field, Code: code, Message: err.Error()})
+}
+
+// WriteValidationErrors writes errs as a 400 with every entry under "errors"
+// "error" summarizes them in one line for clients that only read that key
+func WriteValidationErrors(w http.ResponseWriter, errs ValidationErrors) {
+	summary := make([]string, len(errs))
+	for i, e := range errs {
+		summary[i] = e.Message
+		// Most messages outside the repo parser already name their field
+		if !strings.HasPrefix(e.Message, e.Field) {
+			summary[i] = e.Field + ": " + e.Message
+		}
+	}
+	writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+		"error":  strings.Join(summary, "; "),
+		"errors": errs,
+	})
+}
+
+// joinFieldPath appends field to the path prefix, e.g. "repos[1]" and "dir" give "repos[1].dir"
+func joinFieldPath(prefix, field string) string {
+	switch {
+	case prefix == "":
+		return field
+	case field == "":
+		return prefix
+	}
+	return prefix + "." + field
+}
+
+// writeJSON writes v as a JSON response body with the given status
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("Failed to encode JSON response: %v", err)
+	}
+}
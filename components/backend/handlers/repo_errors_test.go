@@ -0,0 +1,108 @@
+//go:build test
+
+package handlers
+
+import (
+	test_constants "ambient-code-backend/tests/constants"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Repo Error Writer", Label(test_constants.LabelUnit, test_constants.LabelHandlers, test_constants.LabelRepoConfig), func() {
+	Describe("WriteRepoError", func() {
+		It("Should map a RepoValidationError to 400 with details", func() {
+			_, parseErr := ParseReposList([]interface{}{
+				map[string]interface{}{"url": "https://github.com/owner/repo.git", "autoPush": "yes"},
+			})
+			Expect(parseErr).To(HaveOccurred())
+
+			rec := httptest.NewRecorder()
+			WriteRepoError(rec, parseErr)
+
+			Expect(rec.Code).To(Equal(http.StatusBadRequest))
+			var body map[string]string
+			Expect(json.Unmarshal(rec.Body.Bytes(), &body)).To(Succeed())
+			Expect(body["field"]).To(Equal("autoPush"))
+			Expect(body["code"]).To(Equal(RepoErrInvalidType))
+			Expect(body["message"]).To(Equal("autoPush must be a boolean or an object"))
+		})
+
+		It("Should keep the repo index in the message", func() {
+			err := atRepoIndex(2, newRepoValidationError("dir", RepoErrDuplicate, "dir %q is used by another repo", "src"))
+
+			Expect(err).To(MatchError(`repos[2]: dir "src" is used by another repo`))
+			Expect(errors.Is(err, ErrRepoDuplicate)).To(BeTrue())
+		})
+
+		It("Should map an unstructured error to a generic 500", func() {
+			rec := httptest.NewRecorder()
+			WriteRepoError(rec, errors.New("dial tcp 10.0.0.1:6443: connection refused"))
+
+			Expect(rec.Code).To(Equal(http.StatusInternalServerError))
+			Expect(rec.Body.String()).NotTo(ContainSubstring("10.0.0.1"))
+			var body map[string]string
+			Expect(json.Unmarshal(rec.Body.Bytes(), &body)).To(Succeed())
+			Expect(body).To(Equal(map[string]string{"error": "Internal server error"}))
+		})
+	})
+
+	Describe("ValidationErrors", func() {
+		It("Should flatten joined errors and prefix repo fields with their index", func() {
+			var errs ValidationErrors
+			errs.Add("", RepoErrInvalidValue, errors.Join(
+				atRepoIndex(0, newRepoValidationError("dir", RepoErrInvalidPath, "dir must be relative")),
+				atRepoIndex(3, errors.Join(
+					newRepoValidationError("repo", RepoErrPolicy, "forbidden org"),
+					newRepoValidationError("output.url", RepoErrInvalidValue, "bad output"),
+				)),
+			))
+
+			Expect(errs).To(Equal(ValidationErrors{
+				{Field: "repos[0].dir", Code: RepoErrInvalidPath, Message: "dir must be relative"},
+				{Field: "repos[3].repo", Code: RepoErrPolicy, Message: "forbidden org"},
+				{Field: "repos[3].output.url", Code: RepoErrInvalidValue, Message: "bad output"},
+			}))
+		})
+
+		It("Should record other errors under the given field and code", func() {
+			var errs ValidationErrors
+			errs.Add("sessionTimeout", RepoErrInvalidValue, errors.New("sessionTimeout: 48h0m0s exceeds the maximum of 24h0m0s"))
+
+			Expect(errs).To(Equal(ValidationErrors{{
+				Field:   "sessionTimeout",
+				Code:    RepoErrInvalidValue,
+				Message: "sessionTimeout: 48h0m0s exceeds the maximum of 24h0m0s",
+			}}))
+		})
+
+		It("Should ignore nil", func() {
+			var errs ValidationErrors
+			errs.Add("env", RepoErrInvalidValue, nil)
+			errs.Add("", RepoErrInvalidValue, errors.Join())
+			Expect(errs).To(BeEmpty())
+		})
+
+		It("Should write every entry in one 400 with a summary", func() {
+			var errs ValidationErrors
+			errs.Add("", RepoErrDuplicate, atRepoIndex(1, newRepoValidationError("primary", RepoErrDuplicate, "primary is already set on repos[0]")))
+			errs.Add("env", RepoErrInvalidValue, errors.New(`env[0]: invalid name "1BAD"`))
+
+			rec := httptest.NewRecorder()
+			WriteValidationErrors(rec, errs)
+
+			Expect(rec.Code).To(Equal(http.StatusBadRequest))
+			var body struct {
+				Error  string       `json:"error"`
+				Errors []FieldError `json:"errors"`
+			}
+			Expect(json.Unmarshal(rec.Body.Bytes(), &body)).To(Succeed())
+			Expect(body.Errors).To(Equal([]FieldError(errs)))
+			Expect(body.Error).To(Equal(`repos[1].primary: primary is already set on repos[0]; env[0]: invalid name "1BAD"`))
+		})
+	})
+})
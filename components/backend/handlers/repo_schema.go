@@ -0,0 +1,280 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"ambient-code-backend/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// repoConfigSchemaID identifies the repo config schema document
+const repoConfigSchemaID = "https://ambient-code.io/schemas/repo-config.json"
+
+// repoConfigSchema is generated once; keep it in sync with ParseRepoMapWithOptions
+var repoConfigSchema = mustMarshalSchema(buildRepoConfigSchema())
+
+// RepoConfigJSONSchema returns a JSON Schema document for a single repo entry as accepted by ParseRepoMap
+// The schema sticks to draft-04 keywords so the widest range of validators can consume it
+func RepoConfigJSONSchema() []byte {
+	out := make([]byte, len(repoConfigSchema))
+	copy(out, repoConfigSchema)
+	return out
+}
+
+// GetRepoConfigSchema serves the repo config JSON Schema
+// GET /api/schema/repos
+func GetRepoConfigSchema(c *gin.Context) {
+	c.Data(http.StatusOK, "application/schema+json", RepoConfigJSONSchema())
+}
+
+func buildRepoConfigSchema() map[string]interface{} {
+	// Non-blank string: the parser treats whitespace-only values as missing
+	nonBlank := func(description string) map[string]interface{} {
+		return map[string]interface{}{
+			"type":        "string",
+			"pattern":     `\S`,
+			"description": description,
+		}
+	}
+	optionalBool := func(description string) map[string]interface{} {
+		return map[string]interface{}{
+			"type":        "boolean",
+			"description": description,
+		}
+	}
+
+	branchFallbacks := func(description string) map[string]interface{} {
+		return map[string]interface{}{
+			"type":        "array",
+			"description": description,
+			"items":       nonBlank("Branch name"),
+		}
+	}
+
+	// Non-blank and not absolute; '..' segments and malformed globs are checked by the parser
+	pathFilters := func(description string) map[string]interface{} {
+		return map[string]interface{}{
+			"type":        "array",
+			"description": description,
+			"items": map[string]interface{}{
+				"type":        "string",
+				"allOf":       []interface{}{map[string]interface{}{"pattern": `\S`}, map[string]interface{}{"pattern": `^[^/\\]`}},
+				"description": "Glob pattern relative to the repo root, e.g. services/api/**",
+			},
+		}
+	}
+
+	baseCommit := func(description string) map[string]interface{} {
+		return map[string]interface{}{
+			"type":        "string",
+			"pattern":     `^([0-9a-fA-F]{40}|[0-9a-fA-F]{64})$`,
+			"description": description,
+		}
+	}
+
+	// A bare bool, or an object that also tunes how pushes happen
+	autoPush := func(description string) map[string]interface{} {
+		return map[string]interface{}{
+			"description": description,
+			"anyOf": []interface{}{
+				map[string]interface{}{"type": "boolean"},
+				map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"enabled": optionalBool("Whether to push (unset = false)"),
+						"squash":  optionalBool("Push the session's changes as a single commit"),
+						"intervalSeconds": map[string]interface{}{
+							"type":        "integer",
+							"minimum":     1,
+							"description": "Push every N seconds while the session runs (unset = only after completion)",
+						},
+						"pushCondition": map[string]interface{}{
+							"type":        "object",
+							"description": "Push only when this command succeeds in the checkout (unset = push unconditionally)",
+							"required":    []string{"command"},
+							"properties": map[string]interface{}{
+								"command": nonBlank("Validation command, e.g. \"make test\""),
+								"successExitCodes": map[string]interface{}{
+									"type":        "array",
+									"description": "Exit codes that count as success (unset = only 0)",
+									"items":       map[string]interface{}{"type": "integer", "minimum": 0, "maximum": 255},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	// Secret name and key syntax (DNS subdomain, config map key) are checked by the parser
+	credentialsSecretRef := func(description string) map[string]interface{} {
+		return map[string]interface{}{
+			"type":        "object",
+			"description": description,
+			"required":    []string{"name", "key"},
+			"properties": map[string]interface{}{
+				"name": nonBlank("Name of a secret in the session's namespace"),
+				"key":  nonBlank("Key within the secret holding the token"),
+			},
+			"additionalProperties": false,
+		}
+	}
+
+	// Locations are inlined rather than shared via $ref, which not every validator resolves
+	location := func(description string) map[string]interface{} {
+		return map[string]interface{}{
+			"type":        "object",
+			"description": description,
+			"required":    []string{"url"},
+			"properties": map[string]interface{}{
+				"url":    nonBlank("Git URL of the repository"),
+				"branch": map[string]interface{}{"type": "string", "description": "Branch name; blank or absent uses the remote default"},
+			},
+		}
+	}
+
+	inputLocation := func() map[string]interface{} {
+		loc := location("V2 form: where to clone from")
+		loc["properties"].(map[string]interface{})["branchFallbacks"] = branchFallbacks("Branches to try in order when branch is unset or missing on the remote")
+		loc["properties"].(map[string]interface{})["baseCommit"] = baseCommit("Full SHA of a commit already fetched; only the range from it to the branch head is fetched")
+		loc["properties"].(map[string]interface{})["credentialsSecretRef"] = credentialsSecretRef("Token used to clone, e.g. a read-only token (unset = output's, else the default credentials)")
+		return loc
+	}
+
+	outputLocation := func() map[string]interface{} {
+		loc := location("V2 form: where to push; must differ from input")
+		loc["properties"].(map[string]interface{})["branch"] = map[string]interface{}{
+			"type":        "string",
+			"description": "Branch to push to; blank or absent uses the input branch when url differs from the input's",
+		}
+		loc["properties"].(map[string]interface{})["createBranch"] = optionalBool("Create the branch from the input HEAD if it doesn't exist (unset = true)")
+		loc["properties"].(map[string]interface{})["forkOwner"] = map[string]interface{}{
+			"type":        "string",
+			"pattern":     forkOwnerRegex.String(),
+			"description": "Push to this owner's fork: url becomes https://<input host>/<forkOwner>/<input repo>",
+		}
+		loc["properties"].(map[string]interface{})["credentialsSecretRef"] = credentialsSecretRef("Token used to push (unset = input's, else the default credentials)")
+		// Exactly one of url or forkOwner names the push target
+		delete(loc, "required")
+		loc["oneOf"] = []interface{}{
+			map[string]interface{}{"required": []string{"url"}},
+			map[string]interface{}{"required": []string{"forkOwner"}},
+		}
+		return loc
+	}
+
+	return map[string]interface{}{
+		"$schema":     "http://json-schema.org/draft-04/schema#",
+		"id":          repoConfigSchemaID,
+		"title":       "Repo config",
+		"description": "A repository entry in the flat form ({url, branch}) or the V2 form ({input, output}); input takes precedence when both are present",
+		"type":        "object",
+		"properties": map[string]interface{}{
+			"url":                  nonBlank("Flat form: Git URL to clone"),
+			"branch":               map[string]interface{}{"type": "string", "description": "Flat form: branch to clone"},
+			"branchFallbacks":      branchFallbacks("Flat form: branches to try in order when branch is unset or missing on the remote"),
+			"baseCommit":           baseCommit("Flat form: full SHA of a commit already fetched; only the range from it to the branch head is fetched"),
+			"credentialsSecretRef": credentialsSecretRef("Flat form: token used to clone and push (unset = the default credentials)"),
+			"input":                inputLocation(),
+			"output":               outputLocation(),
+			"autoPush":             autoPush("Commit and push changes after the session completes (unset = false)"),
+			"autopush":             autoPush("Alias of autoPush"),
+			"auto_push":            autoPush("Alias of autoPush"),
+			"recurseSubmodules":    optionalBool("Clone with --recurse-submodules (unset = runner default)"),
+			"readOnly":             optionalBool("Reference material: mounted read-only and may not specify an output"),
+			"primary":              optionalBool("The agent's main working repo; at most one repo may set it (unset on every repo = the first repo)"),
+			"priority": map[string]interface{}{
+				"type":        "integer",
+				"minimum":     minRepoPriority,
+				"maximum":     maxRepoPriority,
+				"description": "Clone order when concurrency is limited: higher clones first (unset = 0)",
+			},
+			"displayName": map[string]interface{}{
+				"type":        "string",
+				"pattern":     `\S`,
+				"maxLength":   maxRepoDisplayNameLength,
+				"description": "Label shown in the UI (unset = owner/repo derived from the URL)",
+			},
+			"labels": map[string]interface{}{
+				"type":          "object",
+				"description":   "Kubernetes labels for the session pod; length limits on key segments are checked by the parser",
+				"maxProperties": maxRepoLabels,
+				// Key syntax: an optional DNS subdomain prefix and a name; patternProperties stands in for propertyNames, which draft-04 lacks
+				"patternProperties": map[string]interface{}{
+					`^([a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*/)?[A-Za-z0-9]([-A-Za-z0-9_.]*[A-Za-z0-9])?$`: map[string]interface{}{
+						"type":      "string",
+						"maxLength": 63,
+						"pattern":   `^([A-Za-z0-9]([-A-Za-z0-9_.]*[A-Za-z0-9])?)?$`,
+					},
+				},
+				"additionalProperties": false,
+			},
+			"includePaths": pathFilters("Paths the agent may edit (unset = the whole repo)"),
+			"excludePaths": pathFilters("Paths the agent must not edit, even when matched by includePaths; a pattern may not appear in both"),
+			"cloneFilter": map[string]interface{}{
+				"type":        "string",
+				"enum":        types.CloneFilters,
+				"description": "Partial clone: treeless or blobless fetch omitted objects on demand (unset = full)",
+			},
+			"depth": map[string]interface{}{
+				"type":        "integer",
+				"minimum":     1,
+				"description": "Shallow clone of this many commits; project limits may clamp it or turn it into a full clone (unset = full history)",
+			},
+			"retries": map[string]interface{}{
+				"type":        "object",
+				"description": "Retries for this repo's clone and push (unset fields use the global settings)",
+				"properties": map[string]interface{}{
+					"maxAttempts": map[string]interface{}{
+						"type":        "integer",
+						"minimum":     types.MinRetryAttempts,
+						"maximum":     types.MaxRetryAttempts,
+						"description": "Attempts including the first, so 1 disables retries",
+					},
+					"initialDelayMs": map[string]interface{}{
+						"type":        "integer",
+						"minimum":     1,
+						"description": "Milliseconds before the first retry; later waits grow exponentially",
+					},
+				},
+				"additionalProperties": false,
+			},
+			"cloneTimeout": map[string]interface{}{
+				"type":        "string",
+				"pattern":     `^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`,
+				"description": "Maximum clone time as a positive Go duration such as \"5m\" (unset = global default)",
+			},
+			"dir": map[string]interface{}{
+				"type":        "string",
+				"pattern":     `\S`,
+				"description": "Checkout directory relative to the workspace (unset = derived from the repo name)",
+				// Absolute paths, .. segments and the workspace root itself are rejected
+				"not": map[string]interface{}{
+					"pattern": `^[/\\]|(^|[/\\])\.\.([/\\]|$)|^\.?/*$`,
+				},
+			},
+		},
+		"anyOf": []interface{}{
+			map[string]interface{}{"required": []string{"input"}},
+			map[string]interface{}{"required": []string{"url"}},
+		},
+		// readOnly: true with an output is contradictory
+		"not": map[string]interface{}{
+			"required": []string{"readOnly", "output"},
+			"properties": map[string]interface{}{
+				"readOnly": map[string]interface{}{"enum": []interface{}{true}},
+			},
+		},
+	}
+}
+
+func mustMarshalSchema(schema map[string]interface{}) []byte {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		panic("repo config schema is not serializable: " + err.Error())
+	}
+	return data
+}
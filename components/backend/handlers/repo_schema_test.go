@@ -0,0 +1,133 @@
+//go:build test
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	test_constants "ambient-code-backend/tests/constants"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+)
+
+var _ = Describe("Repo Config Schema", Label(test_constants.LabelUnit, test_constants.LabelHandlers, test_constants.LabelRepoConfig), func() {
+	var validator *validate.SchemaValidator
+
+	BeforeEach(func() {
+		var schema spec.Schema
+		Expect(json.Unmarshal(RepoConfigJSONSchema(), &schema)).To(Succeed())
+		validator = validate.NewSchemaValidator(&schema, nil, "", strfmt.Default)
+	})
+
+	// Each config is checked against both the schema and ParseRepoMap so the two can't drift apart
+	DescribeTable("Should agree with ParseRepoMap",
+		func(config string, valid bool) {
+			var m map[string]interface{}
+			Expect(json.Unmarshal([]byte(config), &m)).To(Succeed())
+
+			_, parseErr := ParseRepoMap(m)
+			Expect(parseErr == nil).To(Equal(valid), "parser verdict for %s: %v", config, parseErr)
+
+			result := validator.Validate(m)
+			Expect(result.IsValid()).To(Equal(valid), "schema verdict for %s: %v", config, result.Errors)
+		},
+		Entry("flat url", `{"url": "https://github.com/owner/repo.git"}`, true),
+		Entry("flat url with branch and flags", `{"url": "https://github.com/owner/repo.git", "branch": "main", "autoPush": true, "recurseSubmodules": false}`, true),
+		Entry("V2 input and output", `{"input": {"url": "https://github.com/up/repo.git", "branch": "main"}, "output": {"url": "https://github.com/fork/repo.git"}}`, true),
+		Entry("readOnly without output", `{"url": "https://github.com/owner/ref.git", "readOnly": true}`, true),
+		Entry("readOnly false with output", `{"input": {"url": "https://github.com/up/repo.git"}, "output": {"url": "https://github.com/fork/repo.git"}, "readOnly": false}`, true),
+		Entry("output with createBranch", `{"input": {"url": "https://github.com/up/repo.git"}, "output": {"url": "https://github.com/fork/repo.git", "createBranch": false}}`, true),
+		Entry("cloneFilter", `{"url": "https://github.com/owner/repo.git", "cloneFilter": "treeless"}`, true),
+		Entry("depth", `{"url": "https://github.com/owner/repo.git", "depth": 1}`, true),
+		Entry("retries", `{"url": "https://github.com/owner/repo.git", "retries": {"maxAttempts": 5, "initialDelayMs": 200}}`, true),
+		Entry("primary", `{"url": "https://github.com/owner/repo.git", "primary": true}`, true),
+		Entry("cloneTimeout", `{"url": "https://github.com/owner/repo.git", "cloneTimeout": "10m"}`, true),
+		Entry("autoPush aliases", `{"url": "https://github.com/owner/repo.git", "autopush": true, "auto_push": true}`, true),
+		Entry("relative dir", `{"url": "https://github.com/owner/repo.git", "dir": "libs/repo"}`, true),
+		Entry("flat branchFallbacks", `{"url": "https://github.com/owner/repo.git", "branchFallbacks": ["release", "main"]}`, true),
+		Entry("input branchFallbacks", `{"input": {"url": "https://github.com/owner/repo.git", "branch": "next", "branchFallbacks": ["main"]}}`, true),
+		Entry("autoPush object", `{"url": "https://github.com/owner/repo.git", "autoPush": {"enabled": true, "squash": true, "intervalSeconds": 300}}`, true),
+		Entry("displayName", `{"url": "https://github.com/owner/repo.git", "displayName": "Backend"}`, true),
+		Entry("priority", `{"url": "https://github.com/owner/repo.git", "priority": -5}`, true),
+		Entry("autoPush pushCondition", `{"url": "https://github.com/owner/repo.git", "autoPush": {"enabled": true, "pushCondition": {"command": "make test", "successExitCodes": [0, 3]}}}`, true),
+		Entry("path filters", `{"url": "https://github.com/owner/repo.git", "includePaths": ["services/api/**", "go.mod"], "excludePaths": ["services/api/testdata/*"]}`, true),
+		Entry("labels", `{"url": "https://github.com/owner/repo.git", "labels": {"team": "platform", "app.kubernetes.io/component": "backend", "empty": ""}}`, true),
+		Entry("baseCommit", `{"url": "https://github.com/owner/repo.git", "branch": "main", "baseCommit": "0123456789abcdef0123456789abcdef01234567"}`, true),
+		Entry("input baseCommit", `{"input": {"url": "https://github.com/owner/repo.git", "baseCommit": "0123456789ABCDEF0123456789ABCDEF0123456789ABCDEF0123456789ABCDEF"}}`, true),
+		Entry("distinct credentialsSecretRefs", `{"input": {"url": "https://github.com/up/repo.git", "credentialsSecretRef": {"name": "github-read", "key": "token"}}, "output": {"url": "https://github.com/fork/repo.git", "credentialsSecretRef": {"name": "github-push", "key": "token"}}}`, true),
+		Entry("flat credentialsSecretRef", `{"url": "https://github.com/owner/repo.git", "credentialsSecretRef": {"name": "github-creds", "key": "token"}}`, true),
+		Entry("output forkOwner", `{"input": {"url": "https://github.com/up/repo.git"}, "output": {"forkOwner": "me", "branch": "fix"}}`, true),
+
+		Entry("missing url", `{"branch": "main"}`, false),
+		Entry("blank url", `{"url": "   "}`, false),
+		Entry("non-string url", `{"url": 42}`, false),
+		Entry("input without url", `{"input": {"branch": "main"}}`, false),
+		Entry("input not an object", `{"input": "https://github.com/owner/repo.git"}`, false),
+		Entry("output without url", `{"url": "https://github.com/owner/repo.git", "output": {"branch": "x"}}`, false),
+		Entry("output forkOwner and url", `{"url": "https://github.com/up/repo.git", "output": {"forkOwner": "me", "url": "https://github.com/me/repo.git"}}`, false),
+		Entry("pushCondition without command", `{"url": "https://github.com/owner/repo.git", "autoPush": {"pushCondition": {"successExitCodes": [0]}}}`, false),
+		Entry("pushCondition blank command", `{"url": "https://github.com/owner/repo.git", "autoPush": {"pushCondition": {"command": " "}}}`, false),
+		Entry("pushCondition exit code out of range", `{"url": "https://github.com/owner/repo.git", "autoPush": {"pushCondition": {"command": "make test", "successExitCodes": [300]}}}`, false),
+		Entry("absolute includePaths entry", `{"url": "https://github.com/owner/repo.git", "includePaths": ["/etc"]}`, false),
+		Entry("blank excludePaths entry", `{"url": "https://github.com/owner/repo.git", "excludePaths": [" "]}`, false),
+		Entry("includePaths not a list", `{"url": "https://github.com/owner/repo.git", "includePaths": "src/**"}`, false),
+		Entry("invalid label key", `{"url": "https://github.com/owner/repo.git", "labels": {"cost center": "x"}}`, false),
+		Entry("invalid label value", `{"url": "https://github.com/owner/repo.git", "labels": {"team": "plat/form"}}`, false),
+		Entry("non-string label value", `{"url": "https://github.com/owner/repo.git", "labels": {"replicas": 3}}`, false),
+		Entry("abbreviated baseCommit", `{"url": "https://github.com/owner/repo.git", "baseCommit": "0123456"}`, false),
+		Entry("non-hex baseCommit", `{"url": "https://github.com/owner/repo.git", "baseCommit": "main"}`, false),
+		Entry("credentialsSecretRef without key", `{"url": "https://github.com/owner/repo.git", "credentialsSecretRef": {"name": "github-creds"}}`, false),
+		Entry("credentialsSecretRef not an object", `{"url": "https://github.com/owner/repo.git", "credentialsSecretRef": "github-creds"}`, false),
+		Entry("invalid forkOwner", `{"url": "https://github.com/up/repo.git", "output": {"forkOwner": "me and you"}}`, false),
+		Entry("non-boolean createBranch", `{"input": {"url": "https://github.com/up/repo.git"}, "output": {"url": "https://github.com/fork/repo.git", "createBranch": "no"}}`, false),
+		Entry("unknown cloneFilter", `{"url": "https://github.com/owner/repo.git", "cloneFilter": "shallow"}`, false),
+		Entry("zero depth", `{"url": "https://github.com/owner/repo.git", "depth": 0}`, false),
+		Entry("non-boolean primary", `{"url": "https://github.com/owner/repo.git", "primary": 1}`, false),
+		Entry("fractional depth", `{"url": "https://github.com/owner/repo.git", "depth": 1.5}`, false),
+		Entry("too many retry attempts", `{"url": "https://github.com/owner/repo.git", "retries": {"maxAttempts": 11}}`, false),
+		Entry("zero retry delay", `{"url": "https://github.com/owner/repo.git", "retries": {"initialDelayMs": 0}}`, false),
+		Entry("garbage cloneTimeout", `{"url": "https://github.com/owner/repo.git", "cloneTimeout": "soon"}`, false),
+		Entry("negative cloneTimeout", `{"url": "https://github.com/owner/repo.git", "cloneTimeout": "-5m"}`, false),
+		Entry("non-boolean autoPush", `{"url": "https://github.com/owner/repo.git", "autoPush": "yes"}`, false),
+		Entry("non-boolean auto_push", `{"url": "https://github.com/owner/repo.git", "auto_push": 1}`, false),
+		Entry("zero autoPush interval", `{"url": "https://github.com/owner/repo.git", "autoPush": {"enabled": true, "intervalSeconds": 0}}`, false),
+		Entry("fractional autoPush interval", `{"url": "https://github.com/owner/repo.git", "autoPush": {"intervalSeconds": 1.5}}`, false),
+		Entry("blank displayName", `{"url": "https://github.com/owner/repo.git", "displayName": " "}`, false),
+		Entry("priority out of range", `{"url": "https://github.com/owner/repo.git", "priority": 5000}`, false),
+		Entry("fractional priority", `{"url": "https://github.com/owner/repo.git", "priority": 1.5}`, false),
+		Entry("non-string displayName", `{"url": "https://github.com/owner/repo.git", "displayName": 7}`, false),
+		Entry("readOnly with output", `{"input": {"url": "https://github.com/up/repo.git"}, "output": {"url": "https://github.com/fork/repo.git"}, "readOnly": true}`, false),
+		Entry("absolute dir", `{"url": "https://github.com/owner/repo.git", "dir": "/etc"}`, false),
+		Entry("dir with parent traversal", `{"url": "https://github.com/owner/repo.git", "dir": "a/../../b"}`, false),
+		Entry("dir at workspace root", `{"url": "https://github.com/owner/repo.git", "dir": "./"}`, false),
+		Entry("branchFallbacks not a list", `{"url": "https://github.com/owner/repo.git", "branchFallbacks": "main"}`, false),
+		Entry("non-string branchFallbacks entry", `{"url": "https://github.com/owner/repo.git", "branchFallbacks": ["main", 7]}`, false),
+		Entry("blank branchFallbacks entry", `{"input": {"url": "https://github.com/owner/repo.git", "branchFallbacks": [" "]}}`, false),
+	)
+
+	It("Should return an independent copy", func() {
+		schema := RepoConfigJSONSchema()
+		schema[0] = 'x'
+		Expect(RepoConfigJSONSchema()[0]).To(Equal(byte('{')))
+	})
+
+	It("Should serve the schema over HTTP", func() {
+		gin.SetMode(gin.TestMode)
+		recorder := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(recorder)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/schema/repos", nil)
+
+		GetRepoConfigSchema(c)
+
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(recorder.Header().Get("Content-Type")).To(Equal("application/schema+json"))
+		Expect(recorder.Body.Bytes()).To(MatchJSON(RepoConfigJSONSchema()))
+	})
+})
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+
+	"ambient-code-backend/types"
+
+	"sigs.k8s.io/yaml"
+)
+
+// ErrInvalidReposYAML is wrapped by ParseReposFromYAML when the document is not YAML or not a repo list;
+// entries that are well-formed YAML but fail validation return a *RepoValidationError instead
+var ErrInvalidReposYAML = errors.New("invalid repos YAML")
+
+// ParseReposFromYAML parses an in-repo .ambient/repos.yaml document with ParseReposList
+// The document is either a bare list of repo entries or a map with the list under a repos key;
+// entries take the same shape as in the API, and an empty document means no repos
+// Other top-level keys are ignored so the file can grow new settings
+func ParseReposFromYAML(data []byte) ([]types.SimpleRepo, error) {
+	// sigs.k8s.io/yaml decodes through JSON, so entries look exactly like a JSON request body
+	var doc interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidReposYAML, err)
+	}
+
+	var list []interface{}
+	switch v := doc.(type) {
+	case nil:
+		return []types.SimpleRepo{}, nil
+	case []interface{}:
+		list = v
+	case map[string]interface{}:
+		raw, ok := v["repos"]
+		if !ok {
+			return nil, fmt.Errorf("%w: missing repos key", ErrInvalidReposYAML)
+		}
+		if raw != nil {
+			if list, ok = raw.([]interface{}); !ok {
+				return nil, fmt.Errorf("%w: repos must be a list", ErrInvalidReposYAML)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("%w: expected a list of repos or a map with a repos key", ErrInvalidReposYAML)
+	}
+	return ParseReposList(list)
+}
@@ -0,0 +1,102 @@
+//go:build test
+
+package handlers
+
+import (
+	"errors"
+	"time"
+
+	test_constants "ambient-code-backend/tests/constants"
+	"ambient-code-backend/types"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ParseReposFromYAML", Label(test_constants.LabelUnit, test_constants.LabelHandlers, test_constants.LabelRepoConfig), func() {
+	want := []types.SimpleRepo{
+		{
+			URL:      "https://github.com/owner/api.git",
+			Branch:   types.StringPtr("main"),
+			Input:    &types.RepoLocation{URL: "https://github.com/owner/api.git", Branch: types.StringPtr("main")},
+			Output:   &types.RepoLocation{URL: "https://github.com/fork/api.git", Branch: types.StringPtr("feature")},
+			AutoPush: types.BoolPtr(true),
+		},
+		{
+			URL:          "https://github.com/owner/docs.git",
+			Input:        &types.RepoLocation{URL: "https://github.com/owner/docs.git"},
+			ReadOnly:     types.BoolPtr(true),
+			Priority:     -5,
+			CloneTimeout: &types.Duration{Duration: 90 * time.Second},
+		},
+	}
+
+	DescribeTable("Should parse both document shapes",
+		func(doc string) {
+			repos, err := ParseReposFromYAML([]byte(doc))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(repos).To(Equal(want))
+		},
+		Entry("wrapping repos key", `
+repos:
+  - input:
+      url: https://github.com/owner/api.git
+      branch: main
+    output:
+      url: https://github.com/fork/api.git
+      branch: feature
+    autoPush: true
+  - url: https://github.com/owner/docs.git
+    readOnly: true
+    priority: -5
+    cloneTimeout: 90s
+`),
+		Entry("bare list", `
+- input: {url: "https://github.com/owner/api.git", branch: main}
+  output: {url: "https://github.com/fork/api.git", branch: feature}
+  autoPush: true
+- {url: "https://github.com/owner/docs.git", readOnly: true, priority: -5, cloneTimeout: 90s}
+`),
+	)
+
+	DescribeTable("Should return no repos for an empty list",
+		func(doc string) {
+			repos, err := ParseReposFromYAML([]byte(doc))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(repos).To(BeEmpty())
+		},
+		Entry("empty document", ""),
+		Entry("comment only", "# no repos yet\n"),
+		Entry("null repos", "repos:\n"),
+		Entry("empty list", "repos: []\n"),
+	)
+
+	DescribeTable("Should report YAML and shape problems as ErrInvalidReposYAML",
+		func(doc, message string) {
+			_, err := ParseReposFromYAML([]byte(doc))
+			Expect(errors.Is(err, ErrInvalidReposYAML)).To(BeTrue())
+			Expect(err).To(MatchError(ContainSubstring(message)))
+			var verr *RepoValidationError
+			Expect(errors.As(err, &verr)).To(BeFalse())
+		},
+		Entry("malformed YAML", "repos:\n  - url: https://github.com/owner/repo.git\n   branch: main\n", "invalid repos YAML"),
+		Entry("unterminated flow list", "repos: [{url: https://github.com/owner/repo.git}\n", "invalid repos YAML"),
+		Entry("missing repos key", "repo:\n  - url: https://github.com/owner/repo.git\n", "missing repos key"),
+		Entry("repos not a list", "repos: https://github.com/owner/repo.git\n", "repos must be a list"),
+		Entry("scalar document", "https://github.com/owner/repo.git\n", "expected a list of repos or a map with a repos key"),
+	)
+
+	It("Should report invalid entries as validation errors with their index", func() {
+		_, err := ParseReposFromYAML([]byte(`
+repos:
+  - url: https://github.com/owner/api.git
+  - url: https://github.com/owner/docs.git
+    cloneTimeout: soon
+`))
+		var verr *RepoValidationError
+		Expect(errors.As(err, &verr)).To(BeTrue())
+		Expect(verr.Field).To(Equal("cloneTimeout"))
+		Expect(err.Error()).To(HavePrefix("repos[1]: "))
+		Expect(errors.Is(err, ErrInvalidReposYAML)).To(BeFalse())
+	})
+})
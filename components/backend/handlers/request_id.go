@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader is the header used to accept and echo request correlation IDs
+const RequestIDHeader = "X-Request-ID"
+
+// Inbound IDs are echoed into logs and headers, so only accept a conservative charset
+var requestIDRegex = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the given request ID
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID stored in ctx, or "" if none
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// requestIDLogPrefix returns a "[req=<id>] " prefix for log lines, or "" when ctx has no ID
+func requestIDLogPrefix(ctx context.Context) string {
+	if id := RequestIDFromContext(ctx); id != "" {
+		return "[req=" + id + "] "
+	}
+	return ""
+}
+
+// RequestIDMiddleware assigns every request a correlation ID
+// Uses the inbound X-Request-ID header when it is well-formed, otherwise generates one
+// The ID is stored on the request context, in the Gin context as "requestID", and echoed on the response
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !requestIDRegex.MatchString(id) {
+			id = uuid.NewString()
+		}
+		c.Set("requestID", id)
+		c.Request = c.Request.WithContext(WithRequestID(c.Request.Context(), id))
+		c.Header(RequestIDHeader, id)
+		c.Next()
+	}
+}
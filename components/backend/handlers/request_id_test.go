@@ -0,0 +1,128 @@
+//go:build test
+
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"time"
+
+	test_constants "ambient-code-backend/tests/constants"
+	"ambient-code-backend/tests/test_utils"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Request IDs", Label(test_constants.LabelUnit, test_constants.LabelHandlers, test_constants.LabelMiddleware), func() {
+	var logBuf *bytes.Buffer
+
+	BeforeEach(func() {
+		logBuf = &bytes.Buffer{}
+		log.SetOutput(logBuf)
+	})
+
+	AfterEach(func() {
+		log.SetOutput(os.Stderr)
+	})
+
+	Describe("WithRequestID", func() {
+		It("Should round-trip the ID through the context", func() {
+			ctx := WithRequestID(context.Background(), "abc-123")
+			Expect(RequestIDFromContext(ctx)).To(Equal("abc-123"))
+			Expect(RequestIDFromContext(context.Background())).To(BeEmpty())
+		})
+	})
+
+	Describe("RetryWithBackoffContext", func() {
+		It("Should include the request ID in retry log lines", func() {
+			ctx := WithRequestID(context.Background(), "retry-req-1")
+			attempts := 0
+			err := RetryWithBackoffContext(ctx, 2, time.Millisecond, time.Millisecond, func() error {
+				attempts++
+				if attempts == 1 {
+					return errors.New("not yet")
+				}
+				return nil
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(logBuf.String()).To(ContainSubstring("[req=retry-req-1] Operation failed (attempt 1/2)"))
+		})
+
+		It("Should stop retrying when the context is cancelled", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			attempts := 0
+			err := RetryWithBackoffContext(ctx, 5, time.Hour, time.Hour, func() error {
+				attempts++
+				return errors.New("still failing")
+			})
+
+			Expect(err).To(HaveOccurred())
+			Expect(attempts).To(Equal(1))
+		})
+	})
+
+	Describe("ValidateSecretAccess", func() {
+		It("Should include the request ID in the RBAC denial log", func() {
+			fakeClients := test_utils.NewTestClientFactory().GetFakeClients()
+			ctx := WithRequestID(context.Background(), "rbac-req-1")
+
+			err := ValidateSecretAccess(ctx, fakeClients.GetK8sClient(), "some-project", "get")
+
+			Expect(err).To(HaveOccurred())
+			Expect(logBuf.String()).To(ContainSubstring("[req=rbac-req-1] RBAC: denied get on secrets"))
+		})
+	})
+
+	Describe("RequestIDMiddleware", func() {
+		var router *gin.Engine
+		var seen string
+
+		BeforeEach(func() {
+			gin.SetMode(gin.TestMode)
+			router = gin.New()
+			router.Use(RequestIDMiddleware())
+			router.GET("/ping", func(c *gin.Context) {
+				seen = RequestIDFromContext(c.Request.Context())
+				c.Status(http.StatusOK)
+			})
+		})
+
+		It("Should propagate and echo an inbound X-Request-ID", func() {
+			req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+			req.Header.Set(RequestIDHeader, "inbound-42")
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			Expect(seen).To(Equal("inbound-42"))
+			Expect(rec.Header().Get(RequestIDHeader)).To(Equal("inbound-42"))
+		})
+
+		It("Should generate an ID when the header is missing", func() {
+			req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			Expect(seen).NotTo(BeEmpty())
+			Expect(rec.Header().Get(RequestIDHeader)).To(Equal(seen))
+		})
+
+		It("Should replace a malformed inbound ID", func() {
+			req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+			req.Header.Set(RequestIDHeader, "bad id\nwith newline")
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			Expect(seen).NotTo(ContainSubstring("bad id"))
+			Expect(rec.Header().Get(RequestIDHeader)).To(Equal(seen))
+		})
+	})
+})
@@ -0,0 +1,282 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// RetryOptions configures RetryWithOptions
+// Operation and Metrics are optional; without Metrics nothing is recorded
+type RetryOptions struct {
+	MaxRetries   int
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+	// Backoff computes the delay between attempts; nil uses ExponentialBackoff{InitialDelay, MaxDelay}
+	Backoff BackoffStrategy
+	// Operation names the retried call in logs and metric labels (e.g. "update-project")
+	Operation string
+	// Metrics records attempts and outcomes when set
+	Metrics *RetryMetrics
+	// Retryable decides whether a failed attempt should be retried; nil retries every error
+	// e.g. func(err error) bool { return ClassifyAPIError(err).Retryable() }
+	Retryable func(error) bool
+	// MaxElapsed bounds the total time spent, including waits: no retry is started whose wait would
+	// end past it (0 = no bound, only MaxRetries applies)
+	MaxElapsed time.Duration
+	// Clock measures elapsed time and waits between attempts; nil uses the real clock
+	Clock Clock
+	// Budget is shared with other calls to cap their retries collectively; nil = no shared cap
+	// MaxRetries and MaxElapsed still apply to this call on its own
+	Budget *RetryBudget
+}
+
+// ErrRetryBudgetExhausted is wrapped by RetryWithOptions when a shared RetryBudget refuses a retry
+var ErrRetryBudgetExhausted = errors.New("shared retry budget exhausted")
+
+// RetryBudget is a pool of retries shared by several RetryWithOptions calls, e.g. one per item of
+// a batch, so a flaky dependency cannot stretch a request by the batch size times each call's budget
+// Every retry (not first attempt) takes a token and every successful call returns one, up to the
+// initial count; retries whose wait would end past the time cap are refused. Safe for concurrent use
+type RetryBudget struct {
+	mu        sync.Mutex
+	tokens    int
+	maxTokens int
+	// deadline is when the budget stops granting retries (zero = no time cap)
+	deadline time.Time
+	clock    Clock
+}
+
+// NewRetryBudget returns a budget of maxRetries retries that lasts maxElapsed from now
+// (0 = no time cap), measured on clock (nil = the real clock)
+func NewRetryBudget(maxRetries int, maxElapsed time.Duration, clock Clock) *RetryBudget {
+	if clock == nil {
+		clock = realClock{}
+	}
+	b := &RetryBudget{tokens: maxRetries, maxTokens: maxRetries, clock: clock}
+	if maxElapsed > 0 {
+		b.deadline = clock.Now().Add(maxElapsed)
+	}
+	return b
+}
+
+// Remaining returns the number of retries the budget would still grant
+func (b *RetryBudget) Remaining() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.tokens
+}
+
+// take claims a token for a retry after waiting delay, reporting false when none is left
+// or the wait would end past the deadline
+func (b *RetryBudget) take(delay time.Duration) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.tokens <= 0 || (!b.deadline.IsZero() && b.clock.Now().Add(delay).After(b.deadline)) {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// replenish returns a token after a successful call
+func (b *RetryBudget) replenish() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.tokens < b.maxTokens {
+		b.tokens++
+	}
+}
+
+// Clock is the time source RetryWithOptions waits on, so tests can advance time instantly
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// realClock is the production Clock backed by the time package
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// Retry outcome label values
+const (
+	RetryOutcomeSuccess   = "success"
+	RetryOutcomeFailure   = "failure"
+	RetryOutcomeCancelled = "cancelled"
+)
+
+// RetryMetrics holds the Prometheus collectors used by RetryWithOptions
+type RetryMetrics struct {
+	attempts *prometheus.HistogramVec
+	outcomes *prometheus.CounterVec
+}
+
+// NewRetryMetrics creates retry collectors and registers them with reg
+// Callers pass their own registry so tests can inspect metrics in isolation
+func NewRetryMetrics(reg prometheus.Registerer) (*RetryMetrics, error) {
+	m := &RetryMetrics{
+		attempts: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "ambient_retry_attempts",
+			Help:    "Number of attempts made per retried call",
+			Buckets: prometheus.LinearBuckets(1, 1, 10),
+		}, []string{"operation"}),
+		outcomes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "ambient_retry_outcomes_total",
+			Help: "Final outcome of retried calls",
+		}, []string{"operation", "outcome"}),
+	}
+	if err := reg.Register(m.attempts); err != nil {
+		return nil, fmt.Errorf("failed to register retry attempts histogram: %w", err)
+	}
+	if err := reg.Register(m.outcomes); err != nil {
+		return nil, fmt.Errorf("failed to register retry outcomes counter: %w", err)
+	}
+	return m, nil
+}
+
+// observe records a finished retried call
+func (m *RetryMetrics) observe(operation string, attempts int, outcome string) {
+	if m == nil {
+		return
+	}
+	m.attempts.WithLabelValues(operation).Observe(float64(attempts))
+	m.outcomes.WithLabelValues(operation, outcome).Inc()
+}
+
+// BackoffStrategy computes how long to wait before the next attempt
+// attempt is zero-based: Delay(0) is the wait after the first failure
+type BackoffStrategy interface {
+	Delay(attempt int) time.Duration
+}
+
+// ExponentialBackoff doubles the delay after each attempt, starting at Initial and capped at Max
+type ExponentialBackoff struct {
+	Initial time.Duration
+	Max     time.Duration
+}
+
+// Delay returns Initial * 2^attempt, capped at Max
+func (b ExponentialBackoff) Delay(attempt int) time.Duration {
+	delay := float64(b.Initial) * math.Pow(2, float64(attempt))
+	if delay > float64(b.Max) {
+		return b.Max
+	}
+	return time.Duration(delay)
+}
+
+// ConstantBackoff waits the same Interval between every attempt
+type ConstantBackoff struct {
+	Interval time.Duration
+}
+
+// Delay returns Interval regardless of attempt
+func (b ConstantBackoff) Delay(attempt int) time.Duration {
+	return b.Interval
+}
+
+// LinearBackoff grows the delay by Step after each attempt, starting at Initial and capped at Max
+type LinearBackoff struct {
+	Initial time.Duration
+	Step    time.Duration
+	Max     time.Duration
+}
+
+// Delay returns Initial + attempt*Step, capped at Max
+func (b LinearBackoff) Delay(attempt int) time.Duration {
+	delay := float64(b.Initial) + float64(attempt)*float64(b.Step)
+	if delay > float64(b.Max) {
+		return b.Max
+	}
+	return time.Duration(delay)
+}
+
+// RetryWithOptions attempts an operation with backoff as configured by opts
+// Checks for context cancellation between retries to avoid wasting resources
+// Retry log lines include the request ID when one is present in ctx
+// Errors that suggest a client delay (429 Retry-After) wait at least that long; see retryDelay
+// Each call is traced as one SpanRetry span carrying the attempt count and outcome
+func RetryWithOptions(ctx context.Context, opts RetryOptions, operation func() error) (err error) {
+	_, span := startSpan(ctx, SpanRetry, AttrRetryOp.String(opts.Operation), AttrRetryMax.Int(opts.MaxRetries))
+	defer func() { endSpan(span, err) }()
+	finish := func(attempts int, outcome string) {
+		opts.Metrics.observe(opts.Operation, attempts, outcome)
+		span.SetAttributes(AttrRetryAttempts.Int(attempts), AttrRetryOutcome.String(outcome))
+	}
+
+	backoff := opts.Backoff
+	if backoff == nil {
+		backoff = ExponentialBackoff{Initial: opts.InitialDelay, Max: opts.MaxDelay}
+	}
+	clock := opts.Clock
+	if clock == nil {
+		clock = realClock{}
+	}
+	start := clock.Now()
+
+	var lastErr error
+	for i := 0; i < opts.MaxRetries; i++ {
+		if err := operation(); err != nil {
+			lastErr = err
+			if opts.Retryable != nil && !opts.Retryable(err) {
+				finish(i+1, RetryOutcomeFailure)
+				return fmt.Errorf("operation failed with non-retryable error: %w", err)
+			}
+			if i < opts.MaxRetries-1 {
+				delay := retryDelay(backoff, i, err)
+				if opts.MaxElapsed > 0 && clock.Now().Sub(start)+delay > opts.MaxElapsed {
+					finish(i+1, RetryOutcomeFailure)
+					return fmt.Errorf("operation gave up after %d attempts, retrying would exceed %v: %w", i+1, opts.MaxElapsed, err)
+				}
+				if opts.Budget != nil && !opts.Budget.take(delay) {
+					finish(i+1, RetryOutcomeFailure)
+					return fmt.Errorf("operation gave up after %d attempts: %w: %w", i+1, ErrRetryBudgetExhausted, err)
+				}
+				HelperLogger.Printf("%s%s failed (attempt %d/%d), retrying in %v: %v", requestIDLogPrefix(ctx), retryOperationName(opts), i+1, opts.MaxRetries, delay, err)
+				select {
+				case <-ctx.Done():
+					finish(i+1, RetryOutcomeCancelled)
+					return fmt.Errorf("operation cancelled after %d attempts: %w", i+1, lastErr)
+				case <-clock.After(delay):
+				}
+				continue
+			}
+		} else {
+			if opts.Budget != nil {
+				opts.Budget.replenish()
+			}
+			finish(i+1, RetryOutcomeSuccess)
+			return nil
+		}
+	}
+	finish(opts.MaxRetries, RetryOutcomeFailure)
+	return fmt.Errorf("operation failed after %d retries: %w", opts.MaxRetries, lastErr)
+}
+
+// retryDelay returns how long to wait after the failed attempt
+// A server-suggested delay (e.g. Retry-After on a 429) is honored as a minimum, even above the backoff cap,
+// so throttled calls never come back sooner than the API server asked
+func retryDelay(backoff BackoffStrategy, attempt int, err error) time.Duration {
+	delay := backoff.Delay(attempt)
+	if seconds, ok := k8serrors.SuggestsClientDelay(err); ok {
+		if suggested := time.Duration(seconds) * time.Second; suggested > delay {
+			return suggested
+		}
+	}
+	return delay
+}
+
+// retryOperationName returns the operation name for log lines
+func retryOperationName(opts RetryOptions) string {
+	if opts.Operation == "" {
+		return "Operation"
+	}
+	return "Operation " + opts.Operation
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// RetryAfterHeader tells clients how long to wait before retrying a 429 or 503
+const RetryAfterHeader = "Retry-After"
+
+// Retry-After bounds shared by every handler
+const (
+	// DefaultRetryAfter is sent with a 503 when nothing suggests a better delay
+	DefaultRetryAfter = 5 * time.Second
+	// MaxRetryAfter caps the header so a misbehaving upstream cannot park clients for hours
+	MaxRetryAfter = 5 * time.Minute
+)
+
+// SetRetryAfter sets the Retry-After header to d in whole seconds, rounded up
+// Any positive delay is at least one second (Retry-After: 0 would invite an immediate retry);
+// delays above MaxRetryAfter are capped and non-positive delays send DefaultRetryAfter
+// Call it before the status is written
+func SetRetryAfter(w http.ResponseWriter, d time.Duration) {
+	switch {
+	case d <= 0:
+		d = DefaultRetryAfter
+	case d > MaxRetryAfter:
+		d = MaxRetryAfter
+	}
+	seconds := (d + time.Second - 1) / time.Second
+	w.Header().Set(RetryAfterHeader, strconv.FormatInt(int64(seconds), 10))
+}
+
+// ParseRetryAfter reads a Retry-After value in either form RFC 9110 allows: delay seconds or
+// an HTTP date, which is measured from now. ok is false for a missing or malformed value;
+// a date in the past is a zero delay
+func ParseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		// Clamp before converting so a huge value cannot overflow the Duration
+		if seconds > int64(MaxRetryAfter/time.Second) {
+			return MaxRetryAfter, true
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	at, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	return max(at.Sub(now), 0), true
+}
+
+// ForwardRetryAfter passes an upstream 429 or 503's Retry-After on to the client through
+// SetRetryAfter, so proxied and local responses carry the same header; an upstream that sent
+// none gets DefaultRetryAfter. Other statuses are left alone
+func ForwardRetryAfter(w http.ResponseWriter, resp *http.Response) {
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return
+	}
+	d, _ := ParseRetryAfter(resp.Header.Get(RetryAfterHeader), time.Now())
+	SetRetryAfter(w, d)
+}
+
+// apiErrorRetryAfter returns the delay the API server suggested with err (e.g. on a 429),
+// or zero so SetRetryAfter falls back to DefaultRetryAfter
+func apiErrorRetryAfter(err error) time.Duration {
+	if seconds, ok := k8serrors.SuggestsClientDelay(err); ok {
+		return time.Duration(seconds) * time.Second
+	}
+	return 0
+}
@@ -0,0 +1,96 @@
+//go:build test
+
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	test_constants "ambient-code-backend/tests/constants"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Retry-After", Label(test_constants.LabelUnit, test_constants.LabelHandlers), func() {
+	DescribeTable("SetRetryAfter should send whole seconds, rounded up",
+		func(d time.Duration, want string) {
+			recorder := httptest.NewRecorder()
+			SetRetryAfter(recorder, d)
+			Expect(recorder.Header().Get("Retry-After")).To(Equal(want))
+		},
+		Entry("one nanosecond", time.Nanosecond, "1"),
+		Entry("sub-second", 300*time.Millisecond, "1"),
+		Entry("exactly one second", time.Second, "1"),
+		Entry("just over one second", 1001*time.Millisecond, "2"),
+		Entry("multi-second", 2500*time.Millisecond, "3"),
+		Entry("whole minutes", 2*time.Minute, "120"),
+		Entry("at the cap", MaxRetryAfter, "300"),
+		Entry("over the cap", 3*time.Hour, "300"),
+		Entry("zero uses the default", time.Duration(0), "5"),
+		Entry("negative uses the default", -time.Second, "5"),
+	)
+
+	It("Should replace an earlier Retry-After", func() {
+		recorder := httptest.NewRecorder()
+		SetRetryAfter(recorder, time.Minute)
+		SetRetryAfter(recorder, time.Second)
+		Expect(recorder.Header().Values("Retry-After")).To(Equal([]string{"1"}))
+	})
+
+	Describe("ParseRetryAfter", func() {
+		now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+		DescribeTable("Should read both forms",
+			func(value string, want time.Duration) {
+				d, ok := ParseRetryAfter(value, now)
+				Expect(ok).To(BeTrue())
+				Expect(d).To(Equal(want))
+			},
+			Entry("seconds", "120", 2*time.Minute),
+			Entry("zero seconds", "0", time.Duration(0)),
+			Entry("padded seconds", " 7 ", 7*time.Second),
+			Entry("huge seconds are capped", "99999999999999", MaxRetryAfter),
+			Entry("HTTP date", "Sun, 01 Mar 2026 12:00:30 GMT", 30*time.Second),
+			Entry("HTTP date in the past", "Sun, 01 Mar 2026 11:00:00 GMT", time.Duration(0)),
+		)
+
+		DescribeTable("Should reject malformed values",
+			func(value string) {
+				_, ok := ParseRetryAfter(value, now)
+				Expect(ok).To(BeFalse())
+			},
+			Entry("empty", ""),
+			Entry("negative", "-5"),
+			Entry("fractional", "1.5"),
+			Entry("words", "later"),
+		)
+	})
+
+	Describe("ForwardRetryAfter", func() {
+		upstream := func(status int, retryAfter string) *http.Response {
+			resp := &http.Response{StatusCode: status, Header: http.Header{}}
+			if retryAfter != "" {
+				resp.Header.Set("Retry-After", retryAfter)
+			}
+			return resp
+		}
+
+		DescribeTable("Should pass on the upstream delay for 429 and 503",
+			func(resp *http.Response, want string) {
+				recorder := httptest.NewRecorder()
+				ForwardRetryAfter(recorder, resp)
+				Expect(recorder.Header().Get("Retry-After")).To(Equal(want))
+			},
+			Entry("429 with seconds", upstream(http.StatusTooManyRequests, "12"), "12"),
+			Entry("503 with seconds", upstream(http.StatusServiceUnavailable, "1"), "1"),
+			Entry("503 with a past date", upstream(http.StatusServiceUnavailable, "Sun, 01 Mar 2026 11:00:00 GMT"), "5"),
+			Entry("503 over the cap", upstream(http.StatusServiceUnavailable, "86400"), "300"),
+			Entry("503 without a header", upstream(http.StatusServiceUnavailable, ""), "5"),
+			Entry("503 with a malformed header", upstream(http.StatusServiceUnavailable, "soon"), "5"),
+			Entry("500 is left alone", upstream(http.StatusInternalServerError, "12"), ""),
+			Entry("404 is left alone", upstream(http.StatusNotFound, ""), ""),
+		)
+	})
+})
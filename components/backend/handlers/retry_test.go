@@ -0,0 +1,408 @@
+//go:build test
+
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	test_constants "ambient-code-backend/tests/constants"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var _ = Describe("Retry", Label(test_constants.LabelUnit, test_constants.LabelHandlers), func() {
+	var (
+		reg     *prometheus.Registry
+		metrics *RetryMetrics
+	)
+
+	BeforeEach(func() {
+		reg = prometheus.NewRegistry()
+		var err error
+		metrics, err = NewRetryMetrics(reg)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	attemptsHistogram := func(operation string) *dto.Histogram {
+		families, err := reg.Gather()
+		Expect(err).NotTo(HaveOccurred())
+		for _, mf := range families {
+			if mf.GetName() != "ambient_retry_attempts" {
+				continue
+			}
+			for _, m := range mf.GetMetric() {
+				for _, label := range m.GetLabel() {
+					if label.GetName() == "operation" && label.GetValue() == operation {
+						return m.GetHistogram()
+					}
+				}
+			}
+		}
+		return nil
+	}
+
+	It("Should record the attempt count for a call that succeeds after retries", func() {
+		attempts := 0
+		err := RetryWithOptions(context.Background(), RetryOptions{
+			MaxRetries:   5,
+			InitialDelay: time.Millisecond,
+			MaxDelay:     time.Millisecond,
+			Operation:    "get-project",
+			Metrics:      metrics,
+		}, func() error {
+			attempts++
+			if attempts < 3 {
+				return errors.New("not yet")
+			}
+			return nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		h := attemptsHistogram("get-project")
+		Expect(h).NotTo(BeNil())
+		Expect(h.GetSampleCount()).To(Equal(uint64(1)))
+		Expect(h.GetSampleSum()).To(Equal(3.0))
+
+		outcomes, err := metrics.outcomes.GetMetricWithLabelValues("get-project", RetryOutcomeSuccess)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(testutil.ToFloat64(outcomes)).To(Equal(1.0))
+	})
+
+	It("Should record a failure when the budget is exhausted", func() {
+		err := RetryWithOptions(context.Background(), RetryOptions{
+			MaxRetries:   2,
+			InitialDelay: time.Millisecond,
+			MaxDelay:     time.Millisecond,
+			Operation:    "update-project",
+			Metrics:      metrics,
+		}, func() error {
+			return errors.New("always failing")
+		})
+		Expect(err).To(HaveOccurred())
+
+		Expect(attemptsHistogram("update-project").GetSampleSum()).To(Equal(2.0))
+		failures, err := metrics.outcomes.GetMetricWithLabelValues("update-project", RetryOutcomeFailure)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(testutil.ToFloat64(failures)).To(Equal(1.0))
+	})
+
+	It("Should stop at the first non-retryable error", func() {
+		attempts := 0
+		err := RetryWithOptions(context.Background(), RetryOptions{
+			MaxRetries:   5,
+			InitialDelay: time.Millisecond,
+			MaxDelay:     time.Millisecond,
+			Retryable:    func(err error) bool { return err.Error() != "permanent" },
+		}, func() error {
+			attempts++
+			return errors.New("permanent")
+		})
+		Expect(err).To(HaveOccurred())
+		Expect(attempts).To(Equal(1))
+	})
+
+	It("Should not require metrics", func() {
+		err := RetryWithOptions(context.Background(), RetryOptions{MaxRetries: 1}, func() error { return nil })
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("Should wait according to the configured backoff strategy", func() {
+		backoff := &recordingBackoff{}
+		attempts := 0
+		err := RetryWithStrategy(context.Background(), 4, backoff, func() error {
+			attempts++
+			return errors.New("transient")
+		})
+		Expect(err).To(HaveOccurred())
+		Expect(attempts).To(Equal(4))
+		Expect(backoff.attempts).To(Equal([]int{0, 1, 2}), "no delay is computed after the final attempt")
+	})
+
+	DescribeTable("Backoff strategy delay sequences",
+		func(strategy BackoffStrategy, want []time.Duration) {
+			got := make([]time.Duration, len(want))
+			for i := range want {
+				got[i] = strategy.Delay(i)
+			}
+			Expect(got).To(Equal(want))
+		},
+		Entry("exponential doubles up to the cap",
+			ExponentialBackoff{Initial: 100 * time.Millisecond, Max: time.Second},
+			[]time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second}),
+		Entry("exponential stays capped for large attempts",
+			ExponentialBackoff{Initial: time.Second, Max: time.Minute},
+			[]time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second, 32 * time.Second, time.Minute, time.Minute}),
+		Entry("constant never changes",
+			ConstantBackoff{Interval: 250 * time.Millisecond},
+			[]time.Duration{250 * time.Millisecond, 250 * time.Millisecond, 250 * time.Millisecond, 250 * time.Millisecond}),
+		Entry("linear grows by step up to the cap",
+			LinearBackoff{Initial: 100 * time.Millisecond, Step: 150 * time.Millisecond, Max: 500 * time.Millisecond},
+			[]time.Duration{100 * time.Millisecond, 250 * time.Millisecond, 400 * time.Millisecond, 500 * time.Millisecond, 500 * time.Millisecond}),
+	)
+
+	DescribeTable("Should honor a server-suggested delay as a minimum",
+		func(err error, want time.Duration) {
+			Expect(retryDelay(ConstantBackoff{Interval: 2 * time.Second}, 0, err)).To(Equal(want))
+		},
+		Entry("plain error uses the backoff", errors.New("transient"), 2*time.Second),
+		Entry("429 with a longer Retry-After waits for the server", k8serrors.NewTooManyRequests("slow down", 30), 30*time.Second),
+		Entry("429 with a shorter Retry-After keeps the backoff", k8serrors.NewTooManyRequests("slow down", 1), 2*time.Second),
+		Entry("wrapped 429 is still recognized", fmt.Errorf("update failed: %w", k8serrors.NewTooManyRequests("slow down", 10)), 10*time.Second),
+		Entry("server timeout with a suggested delay", k8serrors.NewServerTimeout(schema.GroupResource{Resource: "agenticsessions"}, "update", 5), 5*time.Second),
+	)
+
+	It("Should wait at least the Retry-After before the next attempt", func() {
+		clock := newFakeClock()
+		var attemptTimes []time.Time
+		err := RetryWithOptions(context.Background(), RetryOptions{
+			MaxRetries: 2,
+			Backoff:    ConstantBackoff{Interval: time.Millisecond},
+			Clock:      clock,
+		}, func() error {
+			attemptTimes = append(attemptTimes, clock.Now())
+			if len(attemptTimes) == 1 {
+				return k8serrors.NewTooManyRequests("slow down", 1)
+			}
+			return nil
+		})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(attemptTimes).To(HaveLen(2))
+		Expect(attemptTimes[1].Sub(attemptTimes[0])).To(BeNumerically(">=", time.Second))
+	})
+
+	Describe("with a fake clock", func() {
+		It("Should run a multi-attempt retry without waiting in real time", func() {
+			clock := newFakeClock()
+			attempts := 0
+			wallStart := time.Now()
+			err := RetryWithOptions(context.Background(), RetryOptions{
+				MaxRetries: 6,
+				Backoff:    ExponentialBackoff{Initial: time.Second, Max: time.Minute},
+				Clock:      clock,
+			}, func() error {
+				attempts++
+				if attempts < 6 {
+					return errors.New("transient")
+				}
+				return nil
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(attempts).To(Equal(6))
+			Expect(clock.waits).To(Equal([]time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second}))
+			Expect(clock.Now().Sub(clock.start)).To(Equal(31 * time.Second))
+			Expect(time.Since(wallStart)).To(BeNumerically("<", 100*time.Millisecond))
+		})
+
+		It("Should give up once the next wait would exceed MaxElapsed", func() {
+			clock := newFakeClock()
+			attempts := 0
+			err := RetryWithOptions(context.Background(), RetryOptions{
+				MaxRetries: 10,
+				Backoff:    ExponentialBackoff{Initial: time.Second, Max: time.Minute},
+				MaxElapsed: 10 * time.Second,
+				Operation:  "budgeted",
+				Metrics:    metrics,
+				Clock:      clock,
+			}, func() error {
+				attempts++
+				return errors.New("transient")
+			})
+
+			// Waits of 1s, 2s and 4s fit in 10s; the next 8s wait would end at 15s
+			Expect(err).To(MatchError(ContainSubstring("gave up after 4 attempts")))
+			Expect(err).To(MatchError(ContainSubstring("transient")))
+			Expect(attempts).To(Equal(4))
+			Expect(clock.Now().Sub(clock.start)).To(Equal(7 * time.Second))
+			Expect(testutil.ToFloat64(metrics.outcomes.WithLabelValues("budgeted", RetryOutcomeFailure))).To(Equal(1.0))
+		})
+
+		It("Should count time spent inside attempts against MaxElapsed", func() {
+			clock := newFakeClock()
+			attempts := 0
+			err := RetryWithOptions(context.Background(), RetryOptions{
+				MaxRetries: 10,
+				Backoff:    ConstantBackoff{Interval: time.Second},
+				MaxElapsed: 5 * time.Second,
+				Clock:      clock,
+			}, func() error {
+				attempts++
+				clock.Advance(2 * time.Second)
+				return errors.New("slow and failing")
+			})
+
+			Expect(err).To(HaveOccurred())
+			// 2s + 1s wait + 2s = 5s elapsed; another 1s wait would exceed the budget
+			Expect(attempts).To(Equal(2))
+		})
+
+		It("Should ignore MaxElapsed when it is zero", func() {
+			clock := newFakeClock()
+			attempts := 0
+			err := RetryWithOptions(context.Background(), RetryOptions{
+				MaxRetries: 5,
+				Backoff:    ConstantBackoff{Interval: time.Hour},
+				Clock:      clock,
+			}, func() error {
+				attempts++
+				return errors.New("transient")
+			})
+
+			Expect(err).To(MatchError(ContainSubstring("failed after 5 retries")))
+			Expect(attempts).To(Equal(5))
+			Expect(clock.Now().Sub(clock.start)).To(Equal(4 * time.Hour))
+		})
+	})
+
+	Describe("with a shared RetryBudget", func() {
+		It("Should throttle a burst of failing operations to the shared budget", func() {
+			budget := NewRetryBudget(6, 0, nil)
+			attempts := 0
+			var exhausted int
+			for range 10 {
+				err := RetryWithOptions(context.Background(), RetryOptions{
+					MaxRetries: 5,
+					Backoff:    ConstantBackoff{},
+					Operation:  "batch-item",
+					Metrics:    metrics,
+					Budget:     budget,
+				}, func() error {
+					attempts++
+					return errors.New("flaky dependency")
+				})
+				Expect(err).To(MatchError(ContainSubstring("flaky dependency")))
+				if errors.Is(err, ErrRetryBudgetExhausted) {
+					exhausted++
+				}
+			}
+
+			// Every item gets its first attempt, but only 6 retries are shared among them
+			Expect(attempts).To(Equal(10 + 6))
+			Expect(budget.Remaining()).To(BeZero())
+			// The first item retries 4 times and the second twice; the other 9 are refused a retry
+			Expect(exhausted).To(Equal(9))
+			Expect(testutil.ToFloat64(metrics.outcomes.WithLabelValues("batch-item", RetryOutcomeFailure))).To(Equal(10.0))
+		})
+
+		It("Should replenish the budget when an operation succeeds", func() {
+			budget := NewRetryBudget(1, 0, nil)
+			for item := range 5 {
+				attempts := 0
+				err := RetryWithOptions(context.Background(), RetryOptions{
+					MaxRetries: 3,
+					Backoff:    ConstantBackoff{},
+					Budget:     budget,
+				}, func() error {
+					attempts++
+					if attempts == 1 {
+						return errors.New("transient")
+					}
+					return nil
+				})
+				Expect(err).NotTo(HaveOccurred(), "item %d", item)
+			}
+			Expect(budget.Remaining()).To(Equal(1))
+		})
+
+		It("Should refuse retries whose wait would end past the time cap", func() {
+			clock := newFakeClock()
+			budget := NewRetryBudget(100, 10*time.Second, clock)
+			attempts := 0
+			for range 3 {
+				_ = RetryWithOptions(context.Background(), RetryOptions{
+					MaxRetries: 10,
+					Backoff:    ConstantBackoff{Interval: 3 * time.Second},
+					Clock:      clock,
+					Budget:     budget,
+				}, func() error {
+					attempts++
+					return errors.New("transient")
+				})
+			}
+
+			// Waits of 3s, 3s and 3s fit in 10s; every later retry would end past it
+			Expect(attempts).To(Equal(3 + 3))
+			Expect(clock.Now().Sub(clock.start)).To(Equal(9 * time.Second))
+			Expect(budget.Remaining()).To(Equal(97))
+		})
+
+		It("Should grant exactly its retries to concurrent operations", func() {
+			budget := NewRetryBudget(20, 0, nil)
+			var (
+				mu       sync.Mutex
+				attempts int
+				wg       sync.WaitGroup
+			)
+			for range 50 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					_ = RetryWithOptions(context.Background(), RetryOptions{
+						MaxRetries: 3,
+						Backoff:    ConstantBackoff{},
+						Budget:     budget,
+					}, func() error {
+						mu.Lock()
+						attempts++
+						mu.Unlock()
+						return errors.New("transient")
+					})
+				}()
+			}
+			wg.Wait()
+
+			Expect(attempts).To(Equal(50 + 20))
+			Expect(budget.Remaining()).To(BeZero())
+		})
+	})
+
+	It("Should keep large exponential attempts at the cap without overflowing", func() {
+		Expect(ExponentialBackoff{Initial: time.Second, Max: time.Minute}.Delay(200)).To(Equal(time.Minute))
+	})
+})
+
+// recordingBackoff records the attempts it is asked about and never waits
+type recordingBackoff struct {
+	attempts []int
+}
+
+func (b *recordingBackoff) Delay(attempt int) time.Duration {
+	b.attempts = append(b.attempts, attempt)
+	return 0
+}
+
+// fakeClock advances instantly: After moves the clock forward and fires immediately
+type fakeClock struct {
+	start time.Time
+	now   time.Time
+	waits []time.Duration
+}
+
+func newFakeClock() *fakeClock {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	return &fakeClock{start: start, now: start}
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.waits = append(c.waits, d)
+	c.Advance(d)
+	fired := make(chan time.Time, 1)
+	fired <- c.now
+	return fired
+}
+
+// Advance moves the clock forward by d, as if an attempt took that long
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
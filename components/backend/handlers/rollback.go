@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// Retry settings for rollback deletes
+const (
+	rollbackRetryAttempts     = 3
+	rollbackRetryInitialDelay = 200 * time.Millisecond
+	rollbackRetryMaxDelay     = 2 * time.Second
+)
+
+// SkipCreateRollback disables cleanup of partially-created resources so failures can be inspected
+// Set via SESSION_CREATE_SKIP_ROLLBACK=true; intended for debugging only
+var SkipCreateRollback = os.Getenv("SESSION_CREATE_SKIP_ROLLBACK") == "true"
+
+// resourceToCreate pairs an object with the resource it should be created as
+type resourceToCreate struct {
+	GVR schema.GroupVersionResource
+	Obj *unstructured.Unstructured
+}
+
+// createdResource records an object created by createResourcesWithRollback
+type createdResource struct {
+	gvr       schema.GroupVersionResource
+	namespace string
+	name      string
+}
+
+// createResourcesWithRollback creates objects in order in the given namespace
+// If any create fails, previously created objects are deleted in reverse order (best-effort, logged)
+// and the original create error is returned
+// opts is passed to every create; with opts.DryRun set nothing is persisted, so nothing is rolled back
+func createResourcesWithRollback(ctx context.Context, dyn dynamic.Interface, namespace string, resources []resourceToCreate, opts v1.CreateOptions) ([]*unstructured.Unstructured, error) {
+	created := make([]*unstructured.Unstructured, 0, len(resources))
+	tracked := make([]createdResource, 0, len(resources))
+
+	for _, res := range resources {
+		obj, err := dyn.Resource(res.GVR).Namespace(namespace).Create(ctx, res.Obj, opts)
+		if err != nil {
+			if len(opts.DryRun) > 0 {
+				return nil, err
+			}
+			if SkipCreateRollback {
+				log.Printf("%sCreate of %s %s/%s failed; rollback skipped, leaving %d resource(s) in place", requestIDLogPrefix(ctx), res.GVR.Resource, namespace, res.Obj.GetName(), len(tracked))
+			} else {
+				rollbackCreatedResources(ctx, dyn, tracked)
+			}
+			return nil, err
+		}
+		created = append(created, obj)
+		if len(opts.DryRun) > 0 {
+			continue
+		}
+		tracked = append(tracked, createdResource{gvr: res.GVR, namespace: namespace, name: obj.GetName()})
+	}
+
+	return created, nil
+}
+
+// rollbackCreatedResources deletes tracked resources in reverse creation order
+// Failures are logged rather than returned so the caller can surface the original error
+func rollbackCreatedResources(ctx context.Context, dyn dynamic.Interface, tracked []createdResource) {
+	// Use a fresh context so a cancelled request still gets cleaned up
+	cleanupCtx, cancel := context.WithTimeout(WithRequestID(context.Background(), RequestIDFromContext(ctx)), 30*time.Second)
+	defer cancel()
+
+	for i := len(tracked) - 1; i >= 0; i-- {
+		res := tracked[i]
+		err := RetryWithOptions(cleanupCtx, RetryOptions{
+			MaxRetries:   rollbackRetryAttempts,
+			InitialDelay: rollbackRetryInitialDelay,
+			MaxDelay:     rollbackRetryMaxDelay,
+			Operation:    "rollback-delete",
+		}, func() error {
+			err := dyn.Resource(res.gvr).Namespace(res.namespace).Delete(cleanupCtx, res.name, v1.DeleteOptions{})
+			if err != nil && !errors.IsNotFound(err) {
+				return fmt.Errorf("failed to delete %s %s/%s: %w", res.gvr.Resource, res.namespace, res.name, err)
+			}
+			return nil
+		})
+		if err != nil {
+			log.Printf("%sRollback: %v", requestIDLogPrefix(ctx), err)
+			continue
+		}
+		log.Printf("%sRollback: deleted %s %s/%s", requestIDLogPrefix(ctx), res.gvr.Resource, res.namespace, res.name)
+	}
+}
@@ -0,0 +1,120 @@
+//go:build test
+
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	test_constants "ambient-code-backend/tests/constants"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var _ = Describe("Create Rollback", Label(test_constants.LabelUnit, test_constants.LabelHandlers, test_constants.LabelSessions), func() {
+	const namespace = "rollback-test"
+	gvr := schema.GroupVersionResource{Group: "", Version: "v1", Resource: "configmaps"}
+
+	var (
+		dyn          *dynamicfake.FakeDynamicClient
+		originalSkip bool
+	)
+
+	newConfigMap := func(name string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion("v1")
+		obj.SetKind("ConfigMap")
+		obj.SetName(name)
+		obj.SetNamespace(namespace)
+		return obj
+	}
+
+	// failOnCreate makes the nth create call fail
+	failOnCreate := func(n int) {
+		calls := 0
+		dyn.PrependReactor("create", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			calls++
+			if calls == n {
+				return true, nil, errors.New("injected create failure")
+			}
+			return false, nil, nil
+		})
+	}
+
+	BeforeEach(func() {
+		originalSkip = SkipCreateRollback
+		SkipCreateRollback = false
+		dyn = dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+			gvr: "ConfigMapList",
+		})
+	})
+
+	AfterEach(func() {
+		SkipCreateRollback = originalSkip
+	})
+
+	resources := func(n int) []resourceToCreate {
+		out := make([]resourceToCreate, 0, n)
+		for i := 1; i <= n; i++ {
+			out = append(out, resourceToCreate{GVR: gvr, Obj: newConfigMap(fmt.Sprintf("obj-%d", i))})
+		}
+		return out
+	}
+
+	It("Should create every resource when nothing fails", func() {
+		created, err := createResourcesWithRollback(context.Background(), dyn, namespace, resources(3), v1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(created).To(HaveLen(3))
+	})
+
+	It("Should delete the first N-1 resources when the Nth create fails", func() {
+		failOnCreate(3)
+
+		_, err := createResourcesWithRollback(context.Background(), dyn, namespace, resources(4), v1.CreateOptions{})
+		Expect(err).To(MatchError("injected create failure"))
+
+		for _, name := range []string{"obj-1", "obj-2"} {
+			_, getErr := dyn.Resource(gvr).Namespace(namespace).Get(context.Background(), name, v1.GetOptions{})
+			Expect(k8serrors.IsNotFound(getErr)).To(BeTrue(), "%s should have been rolled back", name)
+		}
+
+		deletes := []string{}
+		for _, action := range dyn.Actions() {
+			if del, ok := action.(k8stesting.DeleteAction); ok {
+				deletes = append(deletes, del.GetName())
+			}
+		}
+		Expect(deletes).To(Equal([]string{"obj-2", "obj-1"}), "rollback should run in reverse creation order")
+	})
+
+	It("Should leave resources in place when rollback is skipped", func() {
+		SkipCreateRollback = true
+		failOnCreate(2)
+
+		_, err := createResourcesWithRollback(context.Background(), dyn, namespace, resources(2), v1.CreateOptions{})
+		Expect(err).To(HaveOccurred())
+
+		_, getErr := dyn.Resource(gvr).Namespace(namespace).Get(context.Background(), "obj-1", v1.GetOptions{})
+		Expect(getErr).NotTo(HaveOccurred())
+	})
+
+	It("Should not roll back a failed dry run, since nothing was persisted", func() {
+		failOnCreate(2)
+
+		_, err := createResourcesWithRollback(context.Background(), dyn, namespace, resources(2), v1.CreateOptions{DryRun: []string{v1.DryRunAll}})
+		Expect(err).To(MatchError("injected create failure"))
+
+		for _, action := range dyn.Actions() {
+			Expect(action.GetVerb()).To(Equal("create"))
+		}
+	})
+})
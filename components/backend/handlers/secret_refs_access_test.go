@@ -0,0 +1,343 @@
+//go:build test
+
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	test_constants "ambient-code-backend/tests/constants"
+	"ambient-code-backend/types"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	authv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var _ = Describe("ValidateSecretRefsAccess", Label(test_constants.LabelUnit, test_constants.LabelHandlers, test_constants.LabelSecrets), func() {
+	const namespace = "tenant-a"
+
+	var (
+		k8sClient *k8sfake.Clientset
+		allowed   map[string]bool
+		checked   []string
+	)
+
+	BeforeEach(func() {
+		allowed = map[string]bool{"github-creds": true, "jira-creds": true}
+		checked = nil
+		k8sClient = k8sfake.NewSimpleClientset()
+		k8sClient.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			ssar := action.(k8stesting.CreateAction).GetObject().(*authv1.SelfSubjectAccessReview)
+			attrs := ssar.Spec.ResourceAttributes
+			checked = append(checked, attrs.Name)
+			ssar.Status.Allowed = attrs.Resource == "secrets" && attrs.Namespace == namespace && allowed[attrs.Name]
+			return true, ssar, nil
+		})
+	})
+
+	It("Should succeed when every referenced secret is accessible", func() {
+		err := ValidateSecretRefsAccess(context.Background(), k8sClient, namespace, []types.SecretRef{
+			{Name: "github-creds", Key: "token"},
+			{Name: "jira-creds", Key: "api-key"},
+		}, "get")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checked).To(Equal([]string{"github-creds", "jira-creds"}))
+	})
+
+	It("Should list every denied secret in one error", func() {
+		err := ValidateSecretRefsAccess(context.Background(), k8sClient, namespace, []types.SecretRef{
+			{Name: "github-creds", Key: "token"},
+			{Name: "prod-db", Key: "password"},
+			{Name: "jira-creds", Key: "api-key"},
+			{Name: "aws", Key: "secret-key"},
+		}, "get")
+
+		var denied *AccessDeniedError
+		Expect(errors.As(err, &denied)).To(BeTrue())
+		Expect(denied.Names).To(Equal([]string{"prod-db", "aws"}))
+		Expect(denied.Namespace).To(Equal(namespace))
+		Expect(err.Error()).To(Equal("user not allowed to get secrets prod-db, aws in namespace tenant-a"))
+	})
+
+	It("Should check each secret only once", func() {
+		err := ValidateSecretRefsAccess(context.Background(), k8sClient, namespace, []types.SecretRef{
+			{Name: "prod-db", Key: "user"},
+			{Name: "prod-db", Key: "password"},
+			{Name: "github-creds", Key: "token"},
+		}, "get")
+
+		var denied *AccessDeniedError
+		Expect(errors.As(err, &denied)).To(BeTrue())
+		Expect(denied.Names).To(Equal([]string{"prod-db"}))
+		Expect(checked).To(Equal([]string{"prod-db", "github-creds"}))
+	})
+
+	It("Should succeed without performing reviews for no refs", func() {
+		Expect(ValidateSecretRefsAccess(context.Background(), k8sClient, namespace, nil, "get")).To(Succeed())
+		Expect(checked).To(BeEmpty())
+	})
+
+	It("Should check distinct input and output repo credentials", func() {
+		repos := []types.SimpleRepo{{
+			Input:  &types.RepoLocation{URL: "https://github.com/upstream/repo.git", CredentialsSecretRef: &types.SecretRef{Name: "github-creds", Key: "token"}},
+			Output: &types.RepoLocation{URL: "https://github.com/fork/repo.git", CredentialsSecretRef: &types.SecretRef{Name: "push-token", Key: "token"}},
+		}}
+
+		err := ValidateSecretRefsAccess(context.Background(), k8sClient, namespace, RepoSecretRefs(repos), "get")
+
+		var denied *AccessDeniedError
+		Expect(errors.As(err, &denied)).To(BeTrue())
+		Expect(denied.Names).To(Equal([]string{"push-token"}))
+		Expect(checked).To(Equal([]string{"github-creds", "push-token"}))
+	})
+
+	It("Should check a single repo credentials ref once", func() {
+		repos := []types.SimpleRepo{{
+			Input:  &types.RepoLocation{URL: "https://github.com/upstream/repo.git"},
+			Output: &types.RepoLocation{URL: "https://github.com/fork/repo.git", CredentialsSecretRef: &types.SecretRef{Name: "github-creds", Key: "token"}},
+		}}
+
+		Expect(ValidateSecretRefsAccess(context.Background(), k8sClient, namespace, RepoSecretRefs(repos), "get")).To(Succeed())
+		Expect(checked).To(Equal([]string{"github-creds"}))
+	})
+
+	It("Should abort on a failed access review", func() {
+		k8sClient.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, errors.New("apiserver unavailable")
+		})
+
+		err := ValidateSecretRefsAccess(context.Background(), k8sClient, namespace, []types.SecretRef{{Name: "github-creds", Key: "token"}}, "get")
+
+		Expect(err).To(MatchError(ContainSubstring("apiserver unavailable")))
+		var denied *AccessDeniedError
+		Expect(errors.As(err, &denied)).To(BeFalse())
+	})
+})
+
+var _ = Describe("CheckSecretAccess", Label(test_constants.LabelUnit, test_constants.LabelHandlers, test_constants.LabelSecrets), func() {
+	var k8sClient *k8sfake.Clientset
+
+	respond := func(status authv1.SubjectAccessReviewStatus, err error) {
+		k8sClient = k8sfake.NewSimpleClientset()
+		k8sClient.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			if err != nil {
+				return true, nil, err
+			}
+			ssar := action.(k8stesting.CreateAction).GetObject().(*authv1.SelfSubjectAccessReview)
+			ssar.Status = status
+			return true, ssar, nil
+		})
+	}
+
+	It("Should report an allowed verb", func() {
+		respond(authv1.SubjectAccessReviewStatus{Allowed: true, Reason: `RBAC: allowed by RoleBinding "edit"`}, nil)
+
+		allowed, reason, err := CheckSecretAccess(context.Background(), k8sClient, "tenant-a", "get")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(allowed).To(BeTrue())
+		Expect(reason).To(Equal(`RBAC: allowed by RoleBinding "edit"`))
+	})
+
+	It("Should report a denial without an error", func() {
+		respond(authv1.SubjectAccessReviewStatus{Allowed: false, Reason: "no RBAC policy matched"}, nil)
+
+		allowed, reason, err := CheckSecretAccess(context.Background(), k8sClient, "tenant-a", "update")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(allowed).To(BeFalse())
+		Expect(reason).To(Equal("no RBAC policy matched"))
+	})
+
+	It("Should return an error when the access review fails", func() {
+		respond(authv1.SubjectAccessReviewStatus{}, errors.New("apiserver unavailable"))
+
+		allowed, _, err := CheckSecretAccess(context.Background(), k8sClient, "tenant-a", "get")
+
+		Expect(err).To(MatchError(ContainSubstring("apiserver unavailable")))
+		Expect(allowed).To(BeFalse())
+	})
+
+	It("Should keep ValidateSecretAccess returning an error on denial", func() {
+		respond(authv1.SubjectAccessReviewStatus{Allowed: false}, nil)
+
+		err := ValidateSecretAccess(context.Background(), k8sClient, "tenant-a", "delete")
+
+		var denied *AccessDeniedError
+		Expect(errors.As(err, &denied)).To(BeTrue())
+		Expect(denied.Namespace).To(Equal("tenant-a"))
+		Expect(denied.Verb).To(Equal("delete"))
+	})
+})
+
+var _ = Describe("ListAllowedVerbs", Label(test_constants.LabelUnit, test_constants.LabelHandlers), func() {
+	var (
+		k8sClient *k8sfake.Clientset
+		reviewed  string
+	)
+
+	respond := func(status authv1.SubjectRulesReviewStatus, err error) {
+		k8sClient = k8sfake.NewSimpleClientset()
+		k8sClient.PrependReactor("create", "selfsubjectrulesreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			if err != nil {
+				return true, nil, err
+			}
+			ssrr := action.(k8stesting.CreateAction).GetObject().(*authv1.SelfSubjectRulesReview)
+			reviewed = ssrr.Spec.Namespace
+			ssrr.Status = status
+			return true, ssrr, nil
+		})
+	}
+
+	rules := []authv1.ResourceRule{
+		{Verbs: []string{"get", "list", "watch"}, APIGroups: []string{"vteam.ambient-code"}, Resources: []string{"agenticsessions"}},
+		{Verbs: []string{"update", "get"}, APIGroups: []string{"vteam.ambient-code"}, Resources: []string{"agenticsessions", "projectsettings"}},
+		{Verbs: []string{"delete"}, APIGroups: []string{"vteam.ambient-code"}, Resources: []string{"agenticsessions"}, ResourceNames: []string{"mine"}},
+		{Verbs: []string{"create"}, APIGroups: []string{""}, Resources: []string{"secrets"}},
+	}
+
+	It("Should return the sorted verbs granted on the resource", func() {
+		respond(authv1.SubjectRulesReviewStatus{ResourceRules: rules}, nil)
+
+		verbs, err := ListAllowedVerbs(context.Background(), k8sClient, "tenant-a", "agenticsessions")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(verbs).To(Equal([]string{"get", "list", "update", "watch"}))
+		Expect(reviewed).To(Equal("tenant-a"))
+	})
+
+	It("Should expand wildcard verbs and resources", func() {
+		respond(authv1.SubjectRulesReviewStatus{ResourceRules: []authv1.ResourceRule{
+			{Verbs: []string{"*"}, APIGroups: []string{"*"}, Resources: []string{"*"}},
+		}}, nil)
+
+		verbs, err := ListAllowedVerbs(context.Background(), k8sClient, "tenant-a", "agenticsessions")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(verbs).To(Equal([]string{"create", "delete", "deletecollection", "get", "list", "patch", "update", "watch"}))
+	})
+
+	It("Should return an empty list when nothing is granted", func() {
+		respond(authv1.SubjectRulesReviewStatus{ResourceRules: rules}, nil)
+
+		verbs, err := ListAllowedVerbs(context.Background(), k8sClient, "tenant-a", "configmaps")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(verbs).NotTo(BeNil())
+		Expect(verbs).To(BeEmpty())
+	})
+
+	It("Should surface an incomplete review together with the verbs found", func() {
+		respond(authv1.SubjectRulesReviewStatus{
+			ResourceRules:   rules,
+			Incomplete:      true,
+			EvaluationError: "webhook authorizer does not support rule listing",
+		}, nil)
+
+		verbs, err := ListAllowedVerbs(context.Background(), k8sClient, "tenant-a", "agenticsessions")
+
+		Expect(errors.Is(err, ErrRulesIncomplete)).To(BeTrue())
+		Expect(err).To(MatchError(ContainSubstring("webhook authorizer does not support rule listing")))
+		Expect(verbs).To(Equal([]string{"get", "list", "update", "watch"}))
+	})
+
+	It("Should surface an evaluation error even when the review is not marked incomplete", func() {
+		respond(authv1.SubjectRulesReviewStatus{ResourceRules: rules, EvaluationError: `role "missing" not found`}, nil)
+
+		_, err := ListAllowedVerbs(context.Background(), k8sClient, "tenant-a", "agenticsessions")
+
+		Expect(errors.Is(err, ErrRulesIncomplete)).To(BeTrue())
+	})
+
+	It("Should use the namespace from the context", func() {
+		respond(authv1.SubjectRulesReviewStatus{ResourceRules: rules}, nil)
+
+		_, err := ListAllowedVerbs(WithNamespace(context.Background(), "tenant-b"), k8sClient, "", "agenticsessions")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reviewed).To(Equal("tenant-b"))
+	})
+
+	It("Should return an error when the rules review fails", func() {
+		respond(authv1.SubjectRulesReviewStatus{}, errors.New("apiserver unavailable"))
+
+		verbs, err := ListAllowedVerbs(context.Background(), k8sClient, "tenant-a", "agenticsessions")
+
+		Expect(err).To(MatchError(ContainSubstring("apiserver unavailable")))
+		Expect(errors.Is(err, ErrRulesIncomplete)).To(BeFalse())
+		Expect(verbs).To(BeNil())
+	})
+})
+
+var _ = Describe("CompareAccess", Label(test_constants.LabelUnit, test_constants.LabelHandlers), func() {
+	var (
+		k8sClient *k8sfake.Clientset
+		reviewed  []authv1.ResourceAttributes
+	)
+
+	gvr := schema.GroupVersionResource{Group: "vteam.ambient-code", Version: "v1alpha1", Resource: "agenticsessions"}
+
+	BeforeEach(func() {
+		reviewed = nil
+		k8sClient = k8sfake.NewSimpleClientset()
+		k8sClient.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			ssar := action.(k8stesting.CreateAction).GetObject().(*authv1.SelfSubjectAccessReview)
+			reviewed = append(reviewed, *ssar.Spec.ResourceAttributes)
+			if ssar.Spec.ResourceAttributes.Namespace == "broken" {
+				return true, nil, errors.New("apiserver unavailable")
+			}
+			ssar.Status.Allowed = ssar.Spec.ResourceAttributes.Namespace == "tenant-a"
+			return true, ssar, nil
+		})
+	})
+
+	It("Should report each namespace's result side by side", func() {
+		allowedA, allowedB, err := CompareAccess(context.Background(), k8sClient, "tenant-a", "tenant-b", gvr, "create")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(allowedA).To(BeTrue())
+		Expect(allowedB).To(BeFalse())
+		Expect(reviewed).To(Equal([]authv1.ResourceAttributes{
+			{Namespace: "tenant-a", Verb: "create", Group: "vteam.ambient-code", Resource: "agenticsessions"},
+			{Namespace: "tenant-b", Verb: "create", Group: "vteam.ambient-code", Resource: "agenticsessions"},
+		}))
+	})
+
+	It("Should review both namespaces inside a request authorized for one of them", func() {
+		ctx := WithNamespace(context.Background(), "tenant-a")
+
+		allowedA, allowedB, err := CompareAccess(ctx, k8sClient, "tenant-a", "tenant-b", gvr, "list")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(allowedA).To(BeTrue())
+		Expect(allowedB).To(BeFalse())
+		Expect(reviewed).To(HaveLen(2))
+		Expect(reviewed[1].Namespace).To(Equal("tenant-b"))
+
+		allowedA, allowedB, err = CompareAccess(ctx, k8sClient, "tenant-b", "tenant-a", gvr, "list")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(allowedA).To(BeFalse())
+		Expect(allowedB).To(BeTrue())
+	})
+
+	It("Should require both namespaces even when ctx carries one", func() {
+		_, _, err := CompareAccess(WithNamespace(context.Background(), "tenant-a"), k8sClient, "tenant-a", "", gvr, "get")
+
+		Expect(err).To(MatchError(ErrNamespaceRequired))
+		Expect(reviewed).To(HaveLen(1))
+	})
+
+	It("Should name the namespace whose review failed", func() {
+		allowedA, allowedB, err := CompareAccess(context.Background(), k8sClient, "tenant-a", "broken", gvr, "get")
+
+		Expect(err).To(MatchError(ContainSubstring("namespace broken: RBAC check failed: apiserver unavailable")))
+		Expect(allowedA).To(BeFalse())
+		Expect(allowedB).To(BeFalse())
+	})
+})
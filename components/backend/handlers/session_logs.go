@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// defaultLogStreamMaxDuration bounds a single log stream when LOG_STREAM_MAX_DURATION is unset
+const defaultLogStreamMaxDuration = 30 * time.Minute
+
+// defaultLogContainer is the runner container whose logs are streamed when none is requested
+const defaultLogContainer = "ambient-code-runner"
+
+// LogStreamMaxDuration is the hard cap on how long a log stream stays open
+// Set via LOG_STREAM_MAX_DURATION (Go duration, e.g. "10m") so idle browser tabs don't hold pod log streams forever
+var LogStreamMaxDuration = logStreamMaxDurationFromEnv()
+
+func logStreamMaxDurationFromEnv() time.Duration {
+	raw := os.Getenv("LOG_STREAM_MAX_DURATION")
+	if raw == "" {
+		return defaultLogStreamMaxDuration
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d <= 0 {
+		log.Printf("Invalid LOG_STREAM_MAX_DURATION %q, using default %s", raw, defaultLogStreamMaxDuration)
+		return defaultLogStreamMaxDuration
+	}
+	return d
+}
+
+// Reasons a log stream ended
+const (
+	logStreamEOF       = "eof"
+	logStreamTimeout   = "timeout"
+	logStreamCancelled = "cancelled"
+	logStreamWriteErr  = "write_error"
+)
+
+// StreamSessionLogs streams the runner pod's logs as server-sent events
+// GET /api/projects/:projectName/agentic-sessions/:sessionName/logs?container=
+func StreamSessionLogs(c *gin.Context) {
+	// Get project from context (set by middleware) or param
+	project := c.GetString("project")
+	if project == "" {
+		project = c.Param("projectName")
+	}
+	sessionName := c.Param("sessionName")
+
+	k8sClt, k8sDyn := GetK8sClientsForRequest(c)
+	if k8sClt == nil || k8sDyn == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or missing token"})
+		c.Abort()
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	gvr := GetAgenticSessionV1Alpha1Resource()
+	session, err := k8sDyn.Resource(gvr).Namespace(project).Get(ctx, sessionName, v1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+			return
+		}
+		log.Printf("%sFailed to get agentic session %s in project %s: %v", requestIDLogPrefix(ctx), sessionName, project, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get agentic session"})
+		return
+	}
+
+	status, _ := session.Object["status"].(map[string]interface{})
+	jobName, _ := status["jobName"].(string)
+	if jobName == "" {
+		jobName = fmt.Sprintf("%s-job", sessionName)
+	}
+
+	pods, err := k8sClt.CoreV1().Pods(project).List(ctx, v1.ListOptions{
+		LabelSelector: fmt.Sprintf("job-name=%s", jobName),
+	})
+	if err != nil {
+		log.Printf("%sFailed to list pods for job %s in project %s: %v", requestIDLogPrefix(ctx), jobName, project, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to find session pod"})
+		return
+	}
+	pod := pickLogPod(pods.Items)
+	if pod == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No pod found for session"})
+		return
+	}
+
+	container := c.Query("container")
+	if container == "" {
+		container = defaultLogContainer
+	}
+
+	// Tie the k8s stream to the request so a client disconnect tears it down
+	stream, err := k8sClt.CoreV1().Pods(project).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Container: container,
+		Follow:    true,
+	}).Stream(ctx)
+	if err != nil {
+		log.Printf("%sFailed to open log stream for pod %s/%s: %v", requestIDLogPrefix(ctx), project, pod.Name, err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to open log stream"})
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	reason := streamLogLines(ctx, c.Writer, stream, LogStreamMaxDuration)
+	log.Printf("%sLog stream for pod %s/%s closed: %s", requestIDLogPrefix(ctx), project, pod.Name, reason)
+}
+
+// pickLogPod prefers a running pod that is not terminating, falling back to the first pod
+func pickLogPod(pods []corev1.Pod) *corev1.Pod {
+	for i := range pods {
+		if pods[i].DeletionTimestamp == nil && pods[i].Status.Phase == corev1.PodRunning {
+			return &pods[i]
+		}
+	}
+	if len(pods) > 0 {
+		return &pods[0]
+	}
+	return nil
+}
+
+// streamLogLines copies stream to w as SSE data events until the stream ends, ctx is done,
+// or maxDuration elapses; on timeout a final SSE comment is sent so clients can tell why it closed
+// The stream is always closed before returning, which also unblocks the reader goroutine
+func streamLogLines(ctx context.Context, w http.ResponseWriter, stream io.ReadCloser, maxDuration time.Duration) string {
+	defer stream.Close()
+
+	flusher, _ := w.(http.Flusher)
+	flush := func() {
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+
+	lines := make(chan string)
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(stream)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			select {
+			case lines <- scanner.Text():
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	timer := time.NewTimer(maxDuration)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return logStreamCancelled
+		case <-timer.C:
+			_, _ = fmt.Fprintf(w, ": stream closed after max duration %s\n\n", maxDuration)
+			flush()
+			return logStreamTimeout
+		case line, ok := <-lines:
+			if !ok {
+				return logStreamEOF
+			}
+			if _, err := fmt.Fprintf(w, "data: %s\n\n", line); err != nil {
+				return logStreamWriteErr
+			}
+			flush()
+		}
+	}
+}
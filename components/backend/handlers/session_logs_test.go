@@ -0,0 +1,84 @@
+//go:build test
+
+package handlers
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	test_constants "ambient-code-backend/tests/constants"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// trackedPipe is a log stream that records whether it was closed
+type trackedPipe struct {
+	*io.PipeReader
+	closed atomic.Bool
+}
+
+func (p *trackedPipe) Close() error {
+	p.closed.Store(true)
+	return p.PipeReader.Close()
+}
+
+var _ = Describe("Session Log Streaming", Label(test_constants.LabelUnit, test_constants.LabelHandlers, test_constants.LabelSessions), func() {
+	var (
+		stream   *trackedPipe
+		writer   *io.PipeWriter
+		recorder *httptest.ResponseRecorder
+	)
+
+	BeforeEach(func() {
+		var reader *io.PipeReader
+		reader, writer = io.Pipe()
+		stream = &trackedPipe{PipeReader: reader}
+		recorder = httptest.NewRecorder()
+	})
+
+	It("Should forward lines and stop at end of stream", func() {
+		go func() {
+			_, _ = writer.Write([]byte("first\nsecond\n"))
+			writer.Close()
+		}()
+
+		reason := streamLogLines(context.Background(), recorder, stream, time.Minute)
+		Expect(reason).To(Equal(logStreamEOF))
+		Expect(recorder.Body.String()).To(Equal("data: first\n\ndata: second\n\n"))
+		Expect(stream.closed.Load()).To(BeTrue())
+	})
+
+	It("Should send a final comment and close the stream after the max duration", func() {
+		go func() {
+			_, _ = writer.Write([]byte("still running\n"))
+			// Keep the stream open without further output
+		}()
+
+		reason := streamLogLines(context.Background(), recorder, stream, 50*time.Millisecond)
+		Expect(reason).To(Equal(logStreamTimeout))
+		Expect(recorder.Body.String()).To(HavePrefix("data: still running\n\n"))
+		Expect(recorder.Body.String()).To(HaveSuffix(": stream closed after max duration 50ms\n\n"))
+		Expect(stream.closed.Load()).To(BeTrue())
+
+		// The reader goroutine is unblocked, so further writes fail rather than hang
+		_, err := writer.Write([]byte("late\n"))
+		Expect(err).To(MatchError(io.ErrClosedPipe))
+	})
+
+	It("Should tear down immediately when the client disconnects", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		result := make(chan string, 1)
+		go func() {
+			result <- streamLogLines(ctx, recorder, stream, time.Minute)
+		}()
+
+		cancel()
+		Eventually(result).Should(Receive(Equal(logStreamCancelled)))
+		Expect(stream.closed.Load()).To(BeTrue())
+		Expect(recorder.Body.String()).NotTo(ContainSubstring("max duration"))
+	})
+})
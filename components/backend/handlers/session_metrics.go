@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	authv1 "k8s.io/api/authorization/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+// DefaultSessionMetricsInterval is how often SessionPhaseCounter.Run recounts sessions
+const DefaultSessionMetricsInterval = 30 * time.Second
+
+// SessionPhaseUnknown counts sessions whose status.phase is outside the CRD enum,
+// so a bad status write cannot add arbitrary label values
+const SessionPhaseUnknown = "Unknown"
+
+// sessionMetricsPageSize bounds each list call so a large cluster is counted in pages
+const sessionMetricsPageSize = 500
+
+// sessionMetricsBackoff spaces out recounts after consecutive list failures
+// A variable rather than a constant so tests can shorten it
+var sessionMetricsBackoff BackoffStrategy = ExponentialBackoff{Initial: time.Second, Max: 5 * time.Minute}
+
+// SessionMetrics backs GET /api/metrics/sessions; nil makes the endpoint report 503
+// Set at startup in main.go
+var SessionMetrics *SessionPhaseCounter
+
+// SessionPhaseCounter counts AgenticSessions in every namespace by status.phase
+// The last count is cached and exported as the ambient_sessions gauge; call Refresh once at
+// startup and Run to keep it current
+type SessionPhaseCounter struct {
+	dynamic dynamic.Interface
+	gauge   *prometheus.GaugeVec
+
+	mu          sync.RWMutex
+	counts      map[string]int
+	refreshedAt time.Time
+	err         error
+}
+
+// SessionPhaseCounts is the JSON body of GET /api/metrics/sessions
+type SessionPhaseCounts struct {
+	// Phases has an entry for every CRD phase, zero when no session is in it
+	Phases map[string]int `json:"phases"`
+	Total  int            `json:"total"`
+	// RefreshedAt is when the counts were last taken
+	RefreshedAt time.Time `json:"refreshedAt"`
+	// Stale is set when the last refresh failed, so the counts are from RefreshedAt
+	Stale bool `json:"stale,omitempty"`
+}
+
+// NewSessionPhaseCounter creates a counter that lists sessions with dyn and registers its gauge with reg
+// Callers pass their own registry so tests can inspect metrics in isolation; nothing is listed until Refresh
+func NewSessionPhaseCounter(dyn dynamic.Interface, reg prometheus.Registerer) (*SessionPhaseCounter, error) {
+	c := &SessionPhaseCounter{
+		dynamic: dyn,
+		gauge: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "ambient_sessions",
+			Help: "Number of agentic sessions by status phase",
+		}, []string{"phase"}),
+	}
+	if err := reg.Register(c.gauge); err != nil {
+		return nil, fmt.Errorf("failed to register sessions gauge: %w", err)
+	}
+	return c, nil
+}
+
+// Refresh lists every session, caches the counts and updates the gauge
+// A failed list keeps the previous counts and records the error, which is returned
+func (c *SessionPhaseCounter) Refresh(ctx context.Context) error {
+	counts, err := c.count(ctx)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.err = err
+	if err != nil {
+		return err
+	}
+	c.counts = counts
+	c.refreshedAt = time.Now()
+	// Reset drops an Unknown series that no longer has sessions
+	c.gauge.Reset()
+	for phase, n := range counts {
+		c.gauge.WithLabelValues(phase).Set(float64(n))
+	}
+	return nil
+}
+
+// Counts returns the cached counts; ok is false until a Refresh has succeeded
+func (c *SessionPhaseCounter) Counts() (counts SessionPhaseCounts, ok bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.counts == nil {
+		return SessionPhaseCounts{}, false
+	}
+	counts = SessionPhaseCounts{Phases: make(map[string]int, len(c.counts)), RefreshedAt: c.refreshedAt}
+	for phase, n := range c.counts {
+		counts.Phases[phase] = n
+		counts.Total += n
+	}
+	counts.Stale = c.err != nil
+	return counts, true
+}
+
+// Err returns the last refresh failure, or nil when the last Refresh succeeded
+func (c *SessionPhaseCounter) Err() error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.err
+}
+
+// Run refreshes the counts every interval until ctx is cancelled
+// After a failed list the next attempt waits according to sessionMetricsBackoff instead, and
+// the first success resets it; errors are logged once per run of failures
+func (c *SessionPhaseCounter) Run(ctx context.Context, interval time.Duration) {
+	failures := 0
+	delay := interval
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+
+		delay = interval
+		if err := c.Refresh(ctx); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			if failures == 0 {
+				HelperLogger.Printf("Session metrics: %v", err)
+			}
+			delay = sessionMetricsBackoff.Delay(failures)
+			failures++
+		} else if failures > 0 {
+			HelperLogger.Printf("Session metrics: listing sessions recovered after %d failed attempts", failures)
+			failures = 0
+		}
+	}
+}
+
+// count lists sessions in every namespace page by page and tallies them by phase
+// A session without a status yet counts as Pending
+func (c *SessionPhaseCounter) count(ctx context.Context) (map[string]int, error) {
+	counts := make(map[string]int, len(validSessionPhases))
+	for phase := range validSessionPhases {
+		counts[phase] = 0
+	}
+
+	gvr := GetAgenticSessionV1Alpha1Resource()
+	opts := v1.ListOptions{Limit: sessionMetricsPageSize}
+	for {
+		list, err := c.dynamic.Resource(gvr).List(ctx, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list sessions: %w", err)
+		}
+		for _, item := range list.Items {
+			counts[sessionPhaseLabel(item)]++
+		}
+		if opts.Continue = list.GetContinue(); opts.Continue == "" {
+			return counts, nil
+		}
+	}
+}
+
+// sessionPhaseLabel returns the gauge label for the session's status.phase
+func sessionPhaseLabel(item unstructured.Unstructured) string {
+	phase, _, _ := unstructured.NestedString(item.Object, "status", "phase")
+	switch {
+	case phase == "":
+		return SessionPhasePending
+	case IsValidSessionPhase(phase):
+		return phase
+	default:
+		return SessionPhaseUnknown
+	}
+}
+
+// canListSessionsClusterWide checks if the user can list AgenticSessions in all namespaces
+// The counts cover every project, so per-project access is not enough to read them
+func canListSessionsClusterWide(ctx context.Context, userClient kubernetes.Interface) (bool, error) {
+	ssar := &authv1.SelfSubjectAccessReview{
+		Spec: authv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authv1.ResourceAttributes{
+				Verb:     "list",
+				Group:    "vteam.ambient-code",
+				Resource: "agenticsessions",
+			},
+		},
+	}
+	result, err := userClient.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, ssar, v1.CreateOptions{})
+	if err != nil {
+		return false, err
+	}
+	return result.Status.Allowed, nil
+}
+
+// GetSessionMetrics returns cluster-wide session counts by phase from the cached SessionMetrics
+// Counts are refreshed in the background, so this never lists sessions itself
+// Requires list on agenticsessions across all namespaces
+// GET /api/metrics/sessions
+func GetSessionMetrics(c *gin.Context) {
+	k8sClt, _ := GetK8sClientsForRequest(c)
+	if k8sClt == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or missing token"})
+		c.Abort()
+		return
+	}
+
+	allowed, err := canListSessionsClusterWide(c.Request.Context(), k8sClt)
+	if err != nil {
+		log.Printf("Failed to check cluster-wide session access: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check permissions"})
+		return
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Cluster-wide session metrics require list access to agenticsessions in all namespaces"})
+		return
+	}
+
+	if SessionMetrics == nil {
+		SetRetryAfter(c.Writer, DefaultSessionMetricsInterval)
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Session metrics are not enabled"})
+		return
+	}
+	counts, ok := SessionMetrics.Counts()
+	if !ok {
+		SetRetryAfter(c.Writer, DefaultSessionMetricsInterval)
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Session metrics are not available yet"})
+		return
+	}
+	c.JSON(http.StatusOK, counts)
+}
@@ -0,0 +1,273 @@
+//go:build test
+
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	test_constants "ambient-code-backend/tests/constants"
+	"ambient-code-backend/tests/test_utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	authv1 "k8s.io/api/authorization/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var _ = Describe("SessionPhaseCounter", Label(test_constants.LabelUnit, test_constants.LabelHandlers, test_constants.LabelSessions), func() {
+	var (
+		dyn     *dynamicfake.FakeDynamicClient
+		reg     *prometheus.Registry
+		counter *SessionPhaseCounter
+		// listFailures is the number of list calls that fail before one succeeds
+		listFailures int
+		lists        int
+		listsMu      sync.Mutex
+	)
+
+	sessionGVR := schema.GroupVersionResource{Group: "vteam.ambient-code", Version: "v1alpha1", Resource: "agenticsessions"}
+
+	session := func(namespace, name, phase string) runtime.Object {
+		obj := map[string]interface{}{
+			"apiVersion": "vteam.ambient-code/v1alpha1",
+			"kind":       "AgenticSession",
+			"metadata":   map[string]interface{}{"name": name, "namespace": namespace},
+		}
+		if phase != "" {
+			obj["status"] = map[string]interface{}{"phase": phase}
+		}
+		return &unstructured.Unstructured{Object: obj}
+	}
+
+	gauge := func(phase string) float64 {
+		return testutil.ToFloat64(counter.gauge.WithLabelValues(phase))
+	}
+
+	BeforeEach(func() {
+		previous := GetAgenticSessionV1Alpha1Resource
+		GetAgenticSessionV1Alpha1Resource = func() schema.GroupVersionResource { return sessionGVR }
+		DeferCleanup(func() { GetAgenticSessionV1Alpha1Resource = previous })
+
+		listFailures = 0
+		lists = 0
+		dyn = dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+			sessionGVR: "AgenticSessionList",
+		},
+			session("tenant-a", "running-1", SessionPhaseRunning),
+			session("tenant-a", "running-2", SessionPhaseRunning),
+			session("tenant-b", "running-3", SessionPhaseRunning),
+			session("tenant-a", "failed-1", SessionPhaseFailed),
+			session("tenant-b", "completed-1", SessionPhaseCompleted),
+			session("tenant-b", "new-1", ""),
+			session("tenant-c", "creating-1", SessionPhaseCreating),
+		)
+		dyn.PrependReactor("list", "agenticsessions", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			listsMu.Lock()
+			defer listsMu.Unlock()
+			lists++
+			if lists <= listFailures {
+				return true, nil, k8serrors.NewServiceUnavailable("apiserver is restarting")
+			}
+			return false, nil, nil
+		})
+
+		reg = prometheus.NewRegistry()
+		var err error
+		counter, err = NewSessionPhaseCounter(dyn, reg)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("Should tally sessions in every namespace by phase", func() {
+		Expect(counter.Refresh(context.Background())).To(Succeed())
+
+		counts, ok := counter.Counts()
+		Expect(ok).To(BeTrue())
+		Expect(counts.Total).To(Equal(7))
+		Expect(counts.Stale).To(BeFalse())
+		Expect(counts.Phases).To(Equal(map[string]int{
+			SessionPhasePending:   1,
+			SessionPhaseCreating:  1,
+			SessionPhaseRunning:   3,
+			SessionPhaseStopping:  0,
+			SessionPhaseStopped:   0,
+			SessionPhaseCompleted: 1,
+			SessionPhaseFailed:    1,
+		}))
+	})
+
+	It("Should export the counts as gauges", func() {
+		Expect(counter.Refresh(context.Background())).To(Succeed())
+
+		Expect(gauge(SessionPhaseRunning)).To(Equal(3.0))
+		Expect(gauge(SessionPhasePending)).To(Equal(1.0))
+		Expect(gauge(SessionPhaseStopped)).To(Equal(0.0))
+		Expect(testutil.CollectAndCount(reg, "ambient_sessions")).To(Equal(len(validSessionPhases)))
+	})
+
+	It("Should count phases outside the CRD enum as Unknown and drop the series once they are gone", func() {
+		Expect(dyn.Tracker().Add(session("tenant-c", "odd-1", "Exploded"))).To(Succeed())
+		Expect(counter.Refresh(context.Background())).To(Succeed())
+		Expect(gauge(SessionPhaseUnknown)).To(Equal(1.0))
+		Expect(testutil.CollectAndCount(reg, "ambient_sessions")).To(Equal(len(validSessionPhases) + 1))
+
+		Expect(dyn.Tracker().Delete(sessionGVR, "tenant-c", "odd-1")).To(Succeed())
+		Expect(counter.Refresh(context.Background())).To(Succeed())
+		Expect(testutil.CollectAndCount(reg, "ambient_sessions")).To(Equal(len(validSessionPhases)))
+	})
+
+	It("Should report no counts before the first successful refresh", func() {
+		listFailures = 1
+
+		err := counter.Refresh(context.Background())
+		Expect(err).To(HaveOccurred())
+		Expect(k8serrors.IsServiceUnavailable(errors.Unwrap(err))).To(BeTrue())
+
+		_, ok := counter.Counts()
+		Expect(ok).To(BeFalse())
+		Expect(counter.Err()).To(HaveOccurred())
+	})
+
+	It("Should keep the last counts and mark them stale when a refresh fails", func() {
+		Expect(counter.Refresh(context.Background())).To(Succeed())
+		listFailures = 2
+
+		Expect(counter.Refresh(context.Background())).NotTo(Succeed())
+
+		counts, ok := counter.Counts()
+		Expect(ok).To(BeTrue())
+		Expect(counts.Total).To(Equal(7))
+		Expect(counts.Stale).To(BeTrue())
+		Expect(gauge(SessionPhaseRunning)).To(Equal(3.0))
+	})
+
+	It("Should back off on list errors and reset once a refresh succeeds", func() {
+		backoff := &recordingBackoff{}
+		previous := sessionMetricsBackoff
+		sessionMetricsBackoff = backoff
+		DeferCleanup(func() { sessionMetricsBackoff = previous })
+		listFailures = 3
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			counter.Run(ctx, time.Millisecond)
+		}()
+
+		Eventually(func() bool {
+			_, ok := counter.Counts()
+			return ok
+		}, time.Second, time.Millisecond).Should(BeTrue())
+		cancel()
+		<-done
+
+		// One backoff step per consecutive failure; later successes wait the interval
+		Expect(backoff.attempts).To(Equal([]int{0, 1, 2}))
+	})
+
+	It("Should report a second counter on the same registry as an error", func() {
+		_, err := NewSessionPhaseCounter(dyn, reg)
+		Expect(err).To(HaveOccurred())
+	})
+
+	Describe("GetSessionMetrics", func() {
+		var (
+			httpUtils *test_utils.HTTPTestUtils
+			k8sUtils  *test_utils.K8sTestUtils
+		)
+
+		BeforeEach(func() {
+			httpUtils = test_utils.NewHTTPTestUtils()
+			k8sUtils = test_utils.NewK8sTestUtils(false, "session-metrics")
+			SetupHandlerDependencies(k8sUtils)
+			GetAgenticSessionV1Alpha1Resource = func() schema.GroupVersionResource { return sessionGVR }
+
+			previous := SessionMetrics
+			SessionMetrics = counter
+			DeferCleanup(func() { SessionMetrics = previous })
+		})
+
+		getMetrics := func(token string) {
+			context := httpUtils.CreateTestGinContext("GET", "/api/metrics/sessions", nil)
+			if token != "" {
+				httpUtils.SetAuthHeader(token)
+			}
+			GetSessionMetrics(context)
+		}
+
+		It("Should return the cached counts as JSON", func() {
+			Expect(counter.Refresh(context.Background())).To(Succeed())
+
+			getMetrics("test-token")
+
+			httpUtils.AssertHTTPStatus(http.StatusOK)
+			var body SessionPhaseCounts
+			httpUtils.GetResponseJSON(&body)
+			Expect(body.Total).To(Equal(7))
+			Expect(body.Phases).To(HaveKeyWithValue(SessionPhaseRunning, 3))
+			Expect(body.Phases).To(HaveKeyWithValue(SessionPhasePending, 1))
+			Expect(body.RefreshedAt).NotTo(BeZero())
+		})
+
+		It("Should return 503 before the first refresh", func() {
+			getMetrics("test-token")
+
+			httpUtils.AssertHTTPStatus(http.StatusServiceUnavailable)
+		})
+
+		It("Should return 503 when session metrics are not enabled", func() {
+			SessionMetrics = nil
+
+			getMetrics("test-token")
+
+			httpUtils.AssertHTTPStatus(http.StatusServiceUnavailable)
+		})
+
+		It("Should review list on agenticsessions across all namespaces", func() {
+			Expect(counter.Refresh(context.Background())).To(Succeed())
+			var reviewed []*authv1.ResourceAttributes
+			k8sUtils.SSARAllowedFunc = func(action k8stesting.Action) bool {
+				ssar := action.(k8stesting.CreateAction).GetObject().(*authv1.SelfSubjectAccessReview)
+				reviewed = append(reviewed, ssar.Spec.ResourceAttributes)
+				return true
+			}
+
+			getMetrics("test-token")
+
+			httpUtils.AssertHTTPStatus(http.StatusOK)
+			Expect(reviewed).To(HaveLen(1))
+			Expect(reviewed[0].Verb).To(Equal("list"))
+			Expect(reviewed[0].Group).To(Equal("vteam.ambient-code"))
+			Expect(reviewed[0].Resource).To(Equal("agenticsessions"))
+			Expect(reviewed[0].Namespace).To(BeEmpty())
+		})
+
+		It("Should return 403 without cluster-wide list access", func() {
+			Expect(counter.Refresh(context.Background())).To(Succeed())
+			k8sUtils.SSARAllowedFunc = func(k8stesting.Action) bool { return false }
+
+			getMetrics("test-token")
+
+			httpUtils.AssertHTTPStatus(http.StatusForbidden)
+		})
+
+		It("Should require authentication", func() {
+			Expect(counter.Refresh(context.Background())).To(Succeed())
+
+			getMetrics("")
+
+			httpUtils.AssertHTTPStatus(http.StatusUnauthorized)
+		})
+	})
+})
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"slices"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// sessionActionVerbs maps each action the UI offers on a session to the verb it needs on agenticsessions
+// start, stop and cancel all update the session object, so they share one check with update
+var sessionActionVerbs = map[string]string{
+	"view":   "get",
+	"update": "update",
+	"start":  "update",
+	"stop":   "update",
+	"cancel": "update",
+	"delete": "delete",
+	// Cloning creates a new session in the project rather than touching this one
+	"clone": "create",
+}
+
+// GetSessionPermissions reports which session actions the caller may perform, so the UI can
+// disable the ones it can't. Each distinct verb is checked once, all in parallel
+// GET /api/projects/:projectName/agentic-sessions/:sessionName/permissions
+func GetSessionPermissions(c *gin.Context) {
+	project := c.GetString("project")
+	sessionName := c.Param("sessionName")
+	gvr := GetAgenticSessionV1Alpha1Resource()
+
+	k8sClt, _ := GetK8sClientsForRequest(c)
+	if k8sClt == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or missing token"})
+		c.Abort()
+		return
+	}
+	ctx := c.Request.Context()
+
+	var verbs []string
+	for _, verb := range sessionActionVerbs {
+		if !slices.Contains(verbs, verb) {
+			verbs = append(verbs, verb)
+		}
+	}
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		allowed  = make(map[string]bool, len(verbs))
+		firstErr error
+	)
+	for _, verb := range verbs {
+		// create applies to the collection; the other verbs to this session
+		name := sessionName
+		if verb == "create" {
+			name = ""
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ok, _, _, err := checkResourceAccess(ctx, k8sClt, project, gvr.Group, gvr.Resource, name, verb)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+			allowed[verb] = ok
+		}()
+	}
+	wg.Wait()
+	if firstErr != nil {
+		log.Printf("GetSessionPermissions: access check for %s/%s failed: %v", project, sessionName, firstErr)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to perform access review"})
+		return
+	}
+
+	permissions := make(map[string]bool, len(sessionActionVerbs))
+	for action, verb := range sessionActionVerbs {
+		permissions[action] = allowed[verb]
+	}
+	c.JSON(http.StatusOK, permissions)
+}
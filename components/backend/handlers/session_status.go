@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+)
+
+// Session phases accepted by the AgenticSession CRD status.phase enum
+const (
+	SessionPhasePending   = "Pending"
+	SessionPhaseCreating  = "Creating"
+	SessionPhaseRunning   = "Running"
+	SessionPhaseStopping  = "Stopping"
+	SessionPhaseStopped   = "Stopped"
+	SessionPhaseCompleted = "Completed"
+	SessionPhaseFailed    = "Failed"
+)
+
+var validSessionPhases = map[string]bool{
+	SessionPhasePending:   true,
+	SessionPhaseCreating:  true,
+	SessionPhaseRunning:   true,
+	SessionPhaseStopping:  true,
+	SessionPhaseStopped:   true,
+	SessionPhaseCompleted: true,
+	SessionPhaseFailed:    true,
+}
+
+// IsValidSessionPhase reports whether phase is one of the CRD's allowed phases
+func IsValidSessionPhase(phase string) bool {
+	return validSessionPhases[phase]
+}
+
+// SessionStatusClient writes AgenticSession status through the status subresource
+// Use it instead of patching status ad hoc so every writer validates phases and retries the same way
+type SessionStatusClient struct {
+	dyn          dynamic.Interface
+	maxRetries   int
+	initialDelay time.Duration
+	maxDelay     time.Duration
+}
+
+// NewSessionStatusClient creates a SessionStatusClient backed by dyn
+func NewSessionStatusClient(dyn dynamic.Interface) *SessionStatusClient {
+	return &SessionStatusClient{
+		dyn:          dyn,
+		maxRetries:   3,
+		initialDelay: 100 * time.Millisecond,
+		maxDelay:     time.Second,
+	}
+}
+
+// SetPhase sets status.phase and status.message on a session
+// Rejects phases outside the CRD enum without calling the API server
+// Conflicts are retried with backoff; any other error is returned immediately
+func (c *SessionStatusClient) SetPhase(ctx context.Context, namespace, name, phase, message string) error {
+	if !IsValidSessionPhase(phase) {
+		return fmt.Errorf("invalid session phase %q", phase)
+	}
+
+	data, err := json.Marshal(map[string]interface{}{
+		"status": map[string]interface{}{
+			"phase":   phase,
+			"message": message,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to build status patch: %w", err)
+	}
+
+	sessions := c.dyn.Resource(GetAgenticSessionV1Alpha1Resource()).Namespace(namespace)
+	err = RetryWithOptions(ctx, RetryOptions{
+		MaxRetries:   c.maxRetries,
+		InitialDelay: c.initialDelay,
+		MaxDelay:     c.maxDelay,
+		Operation:    "set-session-phase",
+		Retryable:    errors.IsConflict,
+	}, func() error {
+		_, err := sessions.Patch(ctx, name, k8stypes.MergePatchType, data, v1.PatchOptions{}, "status")
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set phase %s on session %s/%s: %w", phase, namespace, name, err)
+	}
+
+	HelperLogger.Printf("%sSession %s/%s phase set to %s", requestIDLogPrefix(ctx), namespace, name, phase)
+	return nil
+}
@@ -0,0 +1,115 @@
+//go:build test
+
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	test_constants "ambient-code-backend/tests/constants"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var _ = Describe("SessionStatusClient", Label(test_constants.LabelUnit, test_constants.LabelHandlers, test_constants.LabelSessions), func() {
+	var (
+		dyn     *dynamicfake.FakeDynamicClient
+		client  *SessionStatusClient
+		patches []k8stesting.PatchAction
+		// conflicts is the number of patch calls that fail with a conflict before one succeeds
+		conflicts int
+	)
+
+	sessionGVR := schema.GroupVersionResource{Group: "vteam.ambient-code", Version: "v1alpha1", Resource: "agenticsessions"}
+
+	BeforeEach(func() {
+		previous := GetAgenticSessionV1Alpha1Resource
+		GetAgenticSessionV1Alpha1Resource = func() schema.GroupVersionResource { return sessionGVR }
+		DeferCleanup(func() { GetAgenticSessionV1Alpha1Resource = previous })
+
+		patches = nil
+		conflicts = 0
+		dyn = dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+			sessionGVR: "AgenticSessionList",
+		})
+		dyn.PrependReactor("patch", "agenticsessions", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			patch := action.(k8stesting.PatchAction)
+			patches = append(patches, patch)
+			if len(patches) <= conflicts {
+				return true, nil, k8serrors.NewConflict(sessionGVR.GroupResource(), patch.GetName(), errors.New("object was modified"))
+			}
+			return true, &unstructured.Unstructured{Object: map[string]interface{}{}}, nil
+		})
+
+		client = NewSessionStatusClient(dyn)
+		client.initialDelay = time.Millisecond
+		client.maxDelay = time.Millisecond
+	})
+
+	It("Should merge-patch phase and message on the status subresource", func() {
+		Expect(client.SetPhase(context.Background(), "tenant-a", "session-1", SessionPhaseRunning, "runner started")).To(Succeed())
+
+		Expect(patches).To(HaveLen(1))
+		patch := patches[0]
+		Expect(patch.GetNamespace()).To(Equal("tenant-a"))
+		Expect(patch.GetName()).To(Equal("session-1"))
+		Expect(patch.GetSubresource()).To(Equal("status"))
+		Expect(patch.GetPatchType()).To(Equal(k8stypes.MergePatchType))
+
+		var body map[string]interface{}
+		Expect(json.Unmarshal(patch.GetPatch(), &body)).To(Succeed())
+		Expect(body).To(Equal(map[string]interface{}{
+			"status": map[string]interface{}{
+				"phase":   "Running",
+				"message": "runner started",
+			},
+		}))
+	})
+
+	It("Should retry on conflict until the patch succeeds", func() {
+		conflicts = 2
+
+		Expect(client.SetPhase(context.Background(), "tenant-a", "session-1", SessionPhaseFailed, "clone failed")).To(Succeed())
+		Expect(patches).To(HaveLen(3))
+	})
+
+	It("Should give up after the retry budget is spent", func() {
+		conflicts = 10
+
+		err := client.SetPhase(context.Background(), "tenant-a", "session-1", SessionPhaseStopped, "")
+		Expect(k8serrors.IsConflict(err)).To(BeTrue())
+		Expect(patches).To(HaveLen(3))
+	})
+
+	It("Should not retry errors other than conflicts", func() {
+		dyn.PrependReactor("patch", "agenticsessions", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			patches = append(patches, action.(k8stesting.PatchAction))
+			return true, nil, k8serrors.NewNotFound(sessionGVR.GroupResource(), "session-1")
+		})
+
+		err := client.SetPhase(context.Background(), "tenant-a", "session-1", SessionPhaseCompleted, "")
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+		Expect(patches).To(HaveLen(1))
+	})
+
+	DescribeTable("Should reject phases outside the CRD enum without calling the API",
+		func(phase string) {
+			err := client.SetPhase(context.Background(), "tenant-a", "session-1", phase, "")
+			Expect(err).To(MatchError(ContainSubstring("invalid session phase")))
+			Expect(dyn.Actions()).To(BeEmpty())
+		},
+		Entry("empty", ""),
+		Entry("wrong case", "running"),
+		Entry("unknown", "Paused"),
+	)
+})
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"ambient-code-backend/types"
+)
+
+// sessionTimeoutAnnotation carries the session's wall-clock budget for the operator to enforce
+const sessionTimeoutAnnotation = "ambient-code.io/session-timeout"
+
+// defaultMaxSessionTimeout caps session timeouts when MAX_SESSION_TIMEOUT is unset
+const defaultMaxSessionTimeout = 24 * time.Hour
+
+// MaxSessionTimeout is the longest session timeout a create request may ask for
+// Set via MAX_SESSION_TIMEOUT (Go duration, e.g. "12h")
+var MaxSessionTimeout = maxSessionTimeoutFromEnv()
+
+func maxSessionTimeoutFromEnv() time.Duration {
+	raw := os.Getenv("MAX_SESSION_TIMEOUT")
+	if raw == "" {
+		return defaultMaxSessionTimeout
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d <= 0 {
+		log.Printf("Invalid MAX_SESSION_TIMEOUT %q, using default %s", raw, defaultMaxSessionTimeout)
+		return defaultMaxSessionTimeout
+	}
+	return d
+}
+
+// ParseSessionTimeout parses a session timeout duration string such as "90m"
+// The value must be positive and no longer than MaxSessionTimeout
+func ParseSessionTimeout(s string) (time.Duration, error) {
+	d, err := types.ParsePositiveDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("sessionTimeout: %w", err)
+	}
+	if d > MaxSessionTimeout {
+		return 0, fmt.Errorf("sessionTimeout: %s exceeds the maximum of %s", d, MaxSessionTimeout)
+	}
+	return d, nil
+}
@@ -0,0 +1,48 @@
+//go:build test
+
+package handlers
+
+import (
+	"time"
+
+	test_constants "ambient-code-backend/tests/constants"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ParseSessionTimeout", Label(test_constants.LabelUnit, test_constants.LabelHandlers), func() {
+	BeforeEach(func() {
+		previous := MaxSessionTimeout
+		MaxSessionTimeout = 24 * time.Hour
+		DeferCleanup(func() { MaxSessionTimeout = previous })
+	})
+
+	DescribeTable("accepts positive durations up to the maximum",
+		func(in string, want time.Duration) {
+			d, err := ParseSessionTimeout(in)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(d).To(Equal(want))
+		},
+		Entry("minutes", "90m", 90*time.Minute),
+		Entry("mixed units", "1h30m", 90*time.Minute),
+		Entry("exactly the maximum", "24h", 24*time.Hour),
+	)
+
+	DescribeTable("rejects invalid durations",
+		func(in, wantErr string) {
+			_, err := ParseSessionTimeout(in)
+			Expect(err).To(MatchError(ContainSubstring(wantErr)))
+		},
+		Entry("over the maximum", "25h", "exceeds the maximum of 24h0m0s"),
+		Entry("negative", "-5m", "must be positive"),
+		Entry("zero", "0s", "must be positive"),
+		Entry("bare number", "300", "invalid duration"),
+	)
+
+	It("Should honour a lowered maximum", func() {
+		MaxSessionTimeout = time.Hour
+		_, err := ParseSessionTimeout("2h")
+		Expect(err).To(MatchError(ContainSubstring("exceeds the maximum of 1h0m0s")))
+	})
+})
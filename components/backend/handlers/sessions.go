@@ -6,6 +6,7 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	stderrors "errors"
 	"fmt"
 	"io"
 	"log"
@@ -14,6 +15,7 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -146,6 +148,29 @@ func parseSpec(spec map[string]interface{}) types.AgenticSessionSpec {
 		}
 	}
 
+	if envList, ok := spec["env"].([]interface{}); ok {
+		for _, it := range envList {
+			m, ok := it.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			e := types.EnvVar{}
+			e.Name, _ = m["name"].(string)
+			if value, ok := m["value"].(string); ok {
+				e.Value = types.StringPtr(value)
+			}
+			if ref, ok := m["valueFromSecret"].(map[string]interface{}); ok {
+				sr := &types.SecretRef{}
+				sr.Name, _ = ref["name"].(string)
+				sr.Key, _ = ref["key"].(string)
+				e.ValueFromSecret = sr
+			}
+			if e.Name != "" {
+				result.Env = append(result.Env, e)
+			}
+		}
+	}
+
 	if userContext, ok := spec["userContext"].(map[string]interface{}); ok {
 		uc := &types.UserContext{}
 		if userID, ok := userContext["userId"].(string); ok {
@@ -179,11 +204,128 @@ func parseSpec(spec map[string]interface{}) types.AgenticSessionSpec {
 			if branch, ok := m["branch"].(string); ok && strings.TrimSpace(branch) != "" {
 				r.Branch = types.StringPtr(branch)
 			}
+			if pr, ok := m["pullRequest"].(bool); ok && pr {
+				r.Input = &types.RepoLocation{URL: r.URL, Branch: r.Branch, PullRequest: true}
+			}
+			if fallbacks, ok := m["branchFallbacks"].([]interface{}); ok {
+				for _, fb := range fallbacks {
+					if branch, ok := fb.(string); ok && strings.TrimSpace(branch) != "" {
+						if r.Input == nil {
+							r.Input = &types.RepoLocation{URL: r.URL, Branch: r.Branch}
+						}
+						r.Input.BranchFallbacks = append(r.Input.BranchFallbacks, branch)
+					}
+				}
+			}
+			if base, ok := m["baseCommit"].(string); ok && strings.TrimSpace(base) != "" {
+				if r.Input == nil {
+					r.Input = &types.RepoLocation{URL: r.URL, Branch: r.Branch}
+				}
+				r.Input.BaseCommit = types.StringPtr(base)
+			}
+			if ref := storedSecretRef(m["credentialsSecretRef"]); ref != nil {
+				if r.Input == nil {
+					r.Input = &types.RepoLocation{URL: r.URL, Branch: r.Branch}
+				}
+				r.Input.CredentialsSecretRef = ref
+			}
 			// Parse autoPush as optional boolean. Preserve nil to allow CRD default.
 			// nil = use default (false), false = explicit no-push, true = explicit push
 			if autoPush, ok := m["autoPush"].(bool); ok {
 				r.AutoPush = types.BoolPtr(autoPush)
 			}
+			if opts, ok := m["autoPushOptions"].(map[string]interface{}); ok {
+				spec := &types.AutoPushSpec{Enabled: r.AutoPush}
+				if squash, ok := opts["squash"].(bool); ok {
+					spec.Squash = types.BoolPtr(squash)
+				}
+				if interval, ok := wholeNumber(opts["intervalSeconds"]); ok && interval > 0 {
+					spec.IntervalSeconds = types.IntPtr(interval)
+				}
+				if cond, ok := opts["pushCondition"].(map[string]interface{}); ok {
+					if command, ok := cond["command"].(string); ok && strings.TrimSpace(command) != "" {
+						spec.PushCondition = &types.PushCondition{Command: command}
+						if codes, ok := cond["successExitCodes"].([]interface{}); ok {
+							for _, c := range codes {
+								if code, ok := wholeNumber(c); ok {
+									spec.PushCondition.SuccessExitCodes = append(spec.PushCondition.SuccessExitCodes, code)
+								}
+							}
+						}
+					}
+				}
+				if spec.Squash != nil || spec.IntervalSeconds != nil || spec.PushCondition != nil {
+					r.AutoPushOptions = spec
+				}
+			}
+			if recurse, ok := m["recurseSubmodules"].(bool); ok {
+				r.RecurseSubmodules = types.BoolPtr(recurse)
+			}
+			if readOnly, ok := m["readOnly"].(bool); ok {
+				r.ReadOnly = types.BoolPtr(readOnly)
+			}
+			if primary, ok := m["primary"].(bool); ok {
+				r.Primary = types.BoolPtr(primary)
+			}
+			if dir, ok := m["dir"].(string); ok && strings.TrimSpace(dir) != "" {
+				r.Dir = types.StringPtr(dir)
+			}
+			if priority, ok := wholeNumber(m["priority"]); ok {
+				r.Priority = priority
+			}
+			if depth, ok := wholeNumber(m["depth"]); ok && depth >= 1 {
+				r.Depth = types.IntPtr(depth)
+			}
+			if retries, ok := m["retries"].(map[string]interface{}); ok {
+				// Keep the stored values that are still in range; the rest fall back to the global settings
+				config := &types.RetryConfig{}
+				if attempts, ok := wholeNumber(retries["maxAttempts"]); ok && attempts >= types.MinRetryAttempts && attempts <= types.MaxRetryAttempts {
+					config.MaxAttempts = types.IntPtr(attempts)
+				}
+				if delay, ok := wholeNumber(retries["initialDelayMs"]); ok && delay > 0 {
+					config.InitialDelayMs = types.IntPtr(delay)
+				}
+				if config.MaxAttempts != nil || config.InitialDelayMs != nil {
+					r.Retries = config
+				}
+			}
+			if displayName, ok := m["displayName"].(string); ok && strings.TrimSpace(displayName) != "" {
+				r.DisplayName = types.StringPtr(displayName)
+			}
+			if labels, ok := m["labels"].(map[string]interface{}); ok {
+				for k, v := range labels {
+					if value, ok := v.(string); ok {
+						if r.Labels == nil {
+							r.Labels = make(map[string]string, len(labels))
+						}
+						r.Labels[k] = value
+					}
+				}
+			}
+			r.IncludePaths = nonBlankStrings(m["includePaths"])
+			r.ExcludePaths = nonBlankStrings(m["excludePaths"])
+			if timeout, ok := m["cloneTimeout"].(string); ok {
+				if d, err := types.ParsePositiveDuration(timeout); err == nil {
+					r.CloneTimeout = &types.Duration{Duration: d}
+				}
+			}
+			if filter, ok := m["cloneFilter"].(string); ok && types.IsValidCloneFilter(filter) {
+				r.CloneFilter = types.StringPtr(filter)
+			}
+			if out, ok := m["output"].(map[string]interface{}); ok {
+				loc := &types.RepoLocation{}
+				loc.URL, _ = out["url"].(string)
+				if branch, ok := out["branch"].(string); ok && strings.TrimSpace(branch) != "" {
+					loc.Branch = types.StringPtr(branch)
+				}
+				if createBranch, ok := out["createBranch"].(bool); ok {
+					loc.CreateBranch = types.BoolPtr(createBranch)
+				}
+				loc.CredentialsSecretRef = storedSecretRef(out["credentialsSecretRef"])
+				if strings.TrimSpace(loc.URL) != "" {
+					r.Output = loc
+				}
+			}
 			if strings.TrimSpace(r.URL) != "" {
 				repos = append(repos, r)
 			}
@@ -209,6 +351,33 @@ func parseSpec(spec map[string]interface{}) types.AgenticSessionSpec {
 	return result
 }
 
+// storedSecretRef returns a stored credentialsSecretRef, or nil unless it has both name and key
+func storedSecretRef(raw interface{}) *types.SecretRef {
+	m, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	ref := &types.SecretRef{}
+	ref.Name, _ = m["name"].(string)
+	ref.Key, _ = m["key"].(string)
+	if strings.TrimSpace(ref.Name) == "" || strings.TrimSpace(ref.Key) == "" {
+		return nil
+	}
+	return ref
+}
+
+// nonBlankStrings returns the non-blank strings of a stored list, skipping other items (nil if none)
+func nonBlankStrings(raw interface{}) []string {
+	items, _ := raw.([]interface{})
+	var out []string
+	for _, item := range items {
+		if s, ok := item.(string); ok && strings.TrimSpace(s) != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
 // parseStatus parses AgenticSessionStatus with detailed reconciliation fields
 func parseStatus(status map[string]interface{}) *types.AgenticSessionStatus {
 	if status == nil {
@@ -236,6 +405,10 @@ func parseStatus(status map[string]interface{}) *types.AgenticSessionStatus {
 		result.Phase = phase
 	}
 
+	if message, ok := status["message"].(string); ok {
+		result.Message = message
+	}
+
 	if startTime, ok := status["startTime"].(string); ok && strings.TrimSpace(startTime) != "" {
 		result.StartTime = types.StringPtr(startTime)
 	}
@@ -373,8 +546,24 @@ func ListSessions(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pagination parameters"})
 		return
 	}
+	if _, set := c.GetQuery("limit"); set && (params.Limit < 1 || params.Limit > types.MaxPaginationLimit) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("limit must be between 1 and %d", types.MaxPaginationLimit)})
+		return
+	}
 	types.NormalizePaginationParams(&params)
 
+	// Stream one session per line for clients that can consume NDJSON
+	if acceptsNDJSON(c) {
+		streamSessionsNDJSON(c, k8sDyn, project, params.Search)
+		return
+	}
+
+	// Cursor pagination: an empty pageToken starts the walk, later pages pass back nextPageToken
+	if _, cursor := c.GetQuery("pageToken"); cursor {
+		listSessionsPage(c, k8sDyn, project, params)
+		return
+	}
+
 	// Build list options with pagination
 	// Note: Kubernetes List with Limit returns a continue token for server-side pagination
 	// We use offset-based pagination on top of fetching all items for search/sort flexibility
@@ -390,28 +579,7 @@ func ListSessions(c *gin.Context) {
 
 	var sessions []types.AgenticSession
 	for _, item := range list.Items {
-		meta, _, err := unstructured.NestedMap(item.Object, "metadata")
-		if err != nil {
-			log.Printf("ListSessions: failed to read metadata for %s/%s: %v", project, item.GetName(), err)
-			meta = map[string]interface{}{}
-		}
-		session := types.AgenticSession{
-			APIVersion: item.GetAPIVersion(),
-			Kind:       item.GetKind(),
-			Metadata:   meta,
-		}
-
-		if spec, found, err := unstructured.NestedMap(item.Object, "spec"); err == nil && found {
-			session.Spec = parseSpec(spec)
-		}
-
-		if status, found, err := unstructured.NestedMap(item.Object, "status"); err == nil && found {
-			session.Status = parseStatus(status)
-		}
-
-		session.AutoBranch = ComputeAutoBranch(item.GetName())
-
-		sessions = append(sessions, session)
+		sessions = append(sessions, sessionFromUnstructured(project, item))
 	}
 
 	// Apply search filter if provided
@@ -440,6 +608,126 @@ func ListSessions(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// listSessionsPage serves one page of sessions using the API server's continue token as the cursor
+// Items come back in server order rather than newest first, and search filters within the page,
+// so a page may hold fewer than limit items while nextPageToken is still set
+func listSessionsPage(c *gin.Context, k8sDyn dynamic.Interface, project string, params types.PaginationParams) {
+	gvr := GetAgenticSessionV1Alpha1Resource()
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	list, err := k8sDyn.Resource(gvr).Namespace(project).List(ctx, v1.ListOptions{
+		Limit:    int64(params.Limit),
+		Continue: params.PageToken,
+	})
+	if err != nil {
+		// Malformed and expired continue tokens are the client's to fix
+		if params.PageToken != "" && (errors.IsBadRequest(err) || errors.IsResourceExpired(err) || errors.IsGone(err)) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired pageToken"})
+			return
+		}
+		log.Printf("Failed to list agentic sessions in project %s: %v", project, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list agentic sessions"})
+		return
+	}
+
+	sessions := make([]types.AgenticSession, 0, len(list.Items))
+	for _, item := range list.Items {
+		sessions = append(sessions, sessionFromUnstructured(project, item))
+	}
+	sessions = filterSessionsBySearch(sessions, params.Search)
+
+	// The API server does not report a total for continue-token lists, so TotalCount covers this page only
+	c.JSON(http.StatusOK, types.PaginatedResponse{
+		Items:         sessions,
+		TotalCount:    len(sessions),
+		Limit:         params.Limit,
+		HasMore:       list.GetContinue() != "",
+		NextPageToken: list.GetContinue(),
+	})
+}
+
+// sessionFromUnstructured converts a listed AgenticSession CR into its API representation
+func sessionFromUnstructured(project string, item unstructured.Unstructured) types.AgenticSession {
+	meta, _, err := unstructured.NestedMap(item.Object, "metadata")
+	if err != nil {
+		log.Printf("ListSessions: failed to read metadata for %s/%s: %v", project, item.GetName(), err)
+		meta = map[string]interface{}{}
+	}
+	session := types.AgenticSession{
+		APIVersion: item.GetAPIVersion(),
+		Kind:       item.GetKind(),
+		Metadata:   meta,
+	}
+
+	if spec, found, err := unstructured.NestedMap(item.Object, "spec"); err == nil && found {
+		session.Spec = parseSpec(spec)
+	}
+
+	if status, found, err := unstructured.NestedMap(item.Object, "status"); err == nil && found {
+		session.Status = parseStatus(status)
+	}
+
+	session.AutoBranch = ComputeAutoBranch(item.GetName())
+	return session
+}
+
+// ndjsonContentType is the media type for newline-delimited JSON streams
+const ndjsonContentType = "application/x-ndjson"
+
+// ndjsonPageSize is the number of sessions fetched from the API server per page when streaming
+const ndjsonPageSize = 100
+
+// acceptsNDJSON reports whether the client asked for a newline-delimited JSON response
+func acceptsNDJSON(c *gin.Context) bool {
+	return strings.Contains(c.GetHeader("Accept"), ndjsonContentType)
+}
+
+// streamSessionsNDJSON writes sessions one JSON object per line as pages arrive from the API server
+// Unlike the array response there is no sorting or offset pagination: sessions are streamed in server order
+// The response is flushed after every page so clients can start consuming early
+func streamSessionsNDJSON(c *gin.Context, k8sDyn dynamic.Interface, project, search string) {
+	gvr := GetAgenticSessionV1Alpha1Resource()
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Minute)
+	defer cancel()
+
+	opts := v1.ListOptions{Limit: ndjsonPageSize}
+	list, err := k8sDyn.Resource(gvr).Namespace(project).List(ctx, opts)
+	if err != nil {
+		log.Printf("Failed to list agentic sessions in project %s: %v", project, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list agentic sessions"})
+		return
+	}
+
+	c.Header("Content-Type", ndjsonContentType)
+	c.Status(http.StatusOK)
+	enc := json.NewEncoder(c.Writer)
+	for {
+		sessions := make([]types.AgenticSession, 0, len(list.Items))
+		for _, item := range list.Items {
+			sessions = append(sessions, sessionFromUnstructured(project, item))
+		}
+		for _, session := range filterSessionsBySearch(sessions, search) {
+			if err := enc.Encode(session); err != nil {
+				log.Printf("ListSessions: NDJSON stream for %s aborted: %v", project, err)
+				return
+			}
+		}
+		c.Writer.Flush()
+
+		if list.GetContinue() == "" {
+			return
+		}
+		opts.Continue = list.GetContinue()
+		list, err = k8sDyn.Resource(gvr).Namespace(project).List(ctx, opts)
+		if err != nil {
+			// Headers are already sent; the truncated stream is the only signal we can give
+			log.Printf("ListSessions: NDJSON stream for %s failed mid-list: %v", project, err)
+			return
+		}
+	}
+}
+
 // filterSessionsBySearch filters sessions by search term (name or displayName)
 func filterSessionsBySearch(sessions []types.AgenticSession, search string) []types.AgenticSession {
 	if search == "" {
@@ -515,6 +803,35 @@ func paginateSessions(sessions []types.AgenticSession, offset, limit int) ([]typ
 	return sessions[offset:end], hasMore, nextOffset
 }
 
+// loadProjectSettings reads the spec of the project's ProjectSettings singleton
+// A project without one has no settings; that is not an error
+func loadProjectSettings(ctx context.Context, dyn dynamic.Interface, project string) (types.ProjectSettings, error) {
+	var settings types.ProjectSettings
+	client, err := ProjectSettingsClient(ctx, dyn, project)
+	if err != nil {
+		return settings, err
+	}
+	obj, err := client.Get(ctx, "projectsettings", v1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return settings, nil
+	}
+	if err != nil {
+		return settings, fmt.Errorf("failed to get project settings: %w", err)
+	}
+	spec, found, err := unstructured.NestedMap(obj.Object, "spec")
+	if err != nil || !found {
+		return settings, err
+	}
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return settings, fmt.Errorf("failed to encode project settings: %w", err)
+	}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return types.ProjectSettings{}, fmt.Errorf("invalid project settings: %w", err)
+	}
+	return settings, nil
+}
+
 func CreateSession(c *gin.Context) {
 	project := c.GetString("project")
 
@@ -530,7 +847,85 @@ func CreateSession(c *gin.Context) {
 		return
 	}
 
-	// Validation for multi-repo can be added here if needed
+	// Every validation problem below is collected and returned together in one 400
+	var verrs ValidationErrors
+
+	// ?dryRun=true runs every create with DryRun=All: admission webhooks and quota see the
+	// session, but nothing is persisted and the would-be object is returned instead
+	createOpts := v1.CreateOptions{}
+	if raw := c.Query("dryRun"); raw != "" {
+		dryRun, err := strconv.ParseBool(raw)
+		if err != nil {
+			verrs.Add("dryRun", RepoErrInvalidValue, fmt.Errorf("dryRun must be true or false"))
+		}
+		if dryRun {
+			createOpts.DryRun = []string{v1.DryRunAll}
+		}
+	}
+
+	// Parse each repo with ParseRepoMap, then fill unset fields from the project's defaults;
+	// everything below sees the merged repos. An entry that fails to parse keeps the fields read
+	// before its error, so the checks across repos still report its other problems
+	repos := make([]types.SimpleRepo, len(req.Repos))
+	if len(req.Repos) > 0 {
+		settings, err := loadProjectSettings(c.Request.Context(), k8sDyn, project)
+		if err != nil {
+			log.Printf("CreateSession: not applying repo defaults for project %s: %v", project, err)
+		}
+		for i, entry := range req.Repos {
+			r, err := parseRepoEntry(c.Request.Context(), entry)
+			if err != nil {
+				verrs.Add("", RepoErrInvalidValue, atRepoIndex(i, err))
+				repos[i] = r
+				continue
+			}
+			r = ApplyRepoDefaults(r, settings)
+			r.Depth = types.EffectiveDepth(r, settings)
+			// Parsing already ran the validators; run them again since defaults may change the outcome
+			if err := runRepoValidators(r); err != nil {
+				verrs.Add("", RepoErrPolicy, atRepoIndex(i, err))
+			}
+			repos[i] = r
+		}
+	}
+
+	// Reject unsafe or colliding checkout dirs before anything is created
+	if _, err := ResolveCheckoutDirs(repos); err != nil {
+		verrs.Add("", RepoErrInvalidValue, err)
+	}
+	verrs.Add("", RepoErrDuplicate, ValidatePrimaryRepo(repos))
+
+	if err := types.ValidateEnvVars(req.Env); err != nil {
+		verrs.Add("env", RepoErrInvalidValue, err)
+	} else {
+		verrs.Add("env", RepoErrInvalidValue, types.ResolveEnvSecretRefs(req.Env, project))
+	}
+	verrs.Add("", RepoErrInvalidValue, resolveRepoSecretRefs(repos, project))
+
+	var sessionTimeout time.Duration
+	if req.SessionTimeout != "" {
+		d, err := ParseSessionTimeout(req.SessionTimeout)
+		verrs.Add("sessionTimeout", RepoErrInvalidValue, err)
+		sessionTimeout = d
+	}
+
+	if len(verrs) > 0 {
+		WriteValidationErrors(c.Writer, verrs)
+		return
+	}
+
+	// The session reads repo credentials on the caller's behalf, so the caller must be able to
+	// read them too; otherwise a session could expose any secret in the project
+	if err := ValidateSecretRefsAccess(c.Request.Context(), reqK8s, project, RepoSecretRefs(repos), "get"); err != nil {
+		var denied *AccessDeniedError
+		if stderrors.As(err, &denied) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("CreateSession: failed to check repo credentials access in project %s: %v", project, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check access to repo credentials"})
+		return
+	}
 
 	// Set defaults for LLM settings if not provided
 	llmSettings := types.LLMSettings{
@@ -573,11 +968,14 @@ func CreateSession(c *gin.Context) {
 		}
 		metadata["labels"] = labels
 	}
-	if len(req.Annotations) > 0 {
+	if len(req.Annotations) > 0 || sessionTimeout > 0 {
 		annotations := map[string]interface{}{}
 		for k, v := range req.Annotations {
 			annotations[k] = v
 		}
+		if sessionTimeout > 0 {
+			annotations[sessionTimeoutAnnotation] = sessionTimeout.String()
+		}
 		metadata["annotations"] = annotations
 	}
 
@@ -629,6 +1027,25 @@ func CreateSession(c *gin.Context) {
 		spec["environmentVariables"] = envVars
 	}
 
+	// Typed env entries (literal values or secret references)
+	if len(req.Env) > 0 {
+		arr := make([]map[string]interface{}, 0, len(req.Env))
+		for _, e := range req.Env {
+			m := map[string]interface{}{"name": e.Name}
+			if e.Value != nil {
+				m["value"] = *e.Value
+			}
+			if e.ValueFromSecret != nil {
+				m["valueFromSecret"] = map[string]interface{}{
+					"name": e.ValueFromSecret.Name,
+					"key":  e.ValueFromSecret.Key,
+				}
+			}
+			arr = append(arr, m)
+		}
+		session["spec"].(map[string]interface{})["env"] = arr
+	}
+
 	// Interactive flag
 	if req.Interactive != nil {
 		session["spec"].(map[string]interface{})["interactive"] = *req.Interactive
@@ -637,19 +1054,102 @@ func CreateSession(c *gin.Context) {
 	// Set multi-repo configuration on spec (simplified format)
 	{
 		spec := session["spec"].(map[string]interface{})
-		if len(req.Repos) > 0 {
-			arr := make([]map[string]interface{}, 0, len(req.Repos))
-			for _, r := range req.Repos {
-				m := map[string]interface{}{"url": r.URL}
+		if len(repos) > 0 {
+			arr := make([]map[string]interface{}, 0, len(repos))
+			for _, r := range repos {
+				input := r.InputLocation()
+				m := map[string]interface{}{"url": input.URL}
 				// Fill in branch if not provided (auto-generate from session name)
-				if r.Branch != nil && strings.TrimSpace(*r.Branch) != "" {
-					m["branch"] = *r.Branch
-				} else {
+				// With fallbacks the first fallback takes that place, so no branch is generated
+				if input.Branch != nil && strings.TrimSpace(*input.Branch) != "" {
+					m["branch"] = *input.Branch
+				} else if len(input.BranchFallbacks) == 0 {
 					m["branch"] = ComputeAutoBranch(name)
 				}
+				if input.PullRequest {
+					m["pullRequest"] = true
+				}
+				if len(input.BranchFallbacks) > 0 {
+					m["branchFallbacks"] = input.BranchFallbacks
+				}
+				if input.BaseCommit != nil {
+					m["baseCommit"] = *input.BaseCommit
+				}
+				if input.CredentialsSecretRef != nil {
+					m["credentialsSecretRef"] = secretRefMap(input.CredentialsSecretRef)
+				}
+				if r.Output != nil {
+					out := map[string]interface{}{"url": r.Output.URL}
+					if r.Output.Branch != nil {
+						out["branch"] = *r.Output.Branch
+					}
+					if r.Output.CreateBranch != nil {
+						out["createBranch"] = *r.Output.CreateBranch
+					}
+					if r.Output.CredentialsSecretRef != nil {
+						out["credentialsSecretRef"] = secretRefMap(r.Output.CredentialsSecretRef)
+					}
+					m["output"] = out
+				}
 				if r.AutoPush != nil {
 					m["autoPush"] = *r.AutoPush
 				}
+				if opts := r.AutoPushOptions; opts != nil {
+					out := map[string]interface{}{}
+					if opts.Squash != nil {
+						out["squash"] = *opts.Squash
+					}
+					if opts.IntervalSeconds != nil {
+						out["intervalSeconds"] = int64(*opts.IntervalSeconds)
+					}
+					if opts.PushCondition != nil {
+						out["pushCondition"] = pushConditionMap(opts.PushCondition)
+					}
+					m["autoPushOptions"] = out
+				}
+				if r.RecurseSubmodules != nil {
+					m["recurseSubmodules"] = *r.RecurseSubmodules
+				}
+				if r.ReadOnly != nil {
+					m["readOnly"] = *r.ReadOnly
+				}
+				if r.Primary != nil {
+					m["primary"] = *r.Primary
+				}
+				if r.Dir != nil {
+					m["dir"] = *r.Dir
+				}
+				if r.CloneTimeout != nil {
+					m["cloneTimeout"] = r.CloneTimeout.String()
+				}
+				if r.CloneFilter != nil {
+					m["cloneFilter"] = *r.CloneFilter
+				}
+				if r.Depth != nil {
+					m["depth"] = int64(*r.Depth)
+				}
+				if r.Retries != nil {
+					m["retries"] = retryConfigMap(r.Retries)
+				}
+				if r.DisplayName != nil {
+					m["displayName"] = *r.DisplayName
+				}
+				if len(r.Labels) > 0 {
+					labels := make(map[string]interface{}, len(r.Labels))
+					for k, v := range r.Labels {
+						labels[k] = v
+					}
+					m["labels"] = labels
+				}
+				if len(r.IncludePaths) > 0 {
+					m["includePaths"] = r.IncludePaths
+				}
+				if len(r.ExcludePaths) > 0 {
+					m["excludePaths"] = r.ExcludePaths
+				}
+				if r.Priority != 0 {
+					m["priority"] = int64(r.Priority)
+				}
 				arr = append(arr, m)
 			}
 			spec["repos"] = arr
@@ -693,12 +1193,33 @@ func CreateSession(c *gin.Context) {
 	obj := &unstructured.Unstructured{Object: session}
 
 	// Create AgenticSession using user token (enforces user RBAC permissions)
-	created, err := k8sDyn.Resource(gvr).Namespace(project).Create(context.TODO(), obj, v1.CreateOptions{})
+	// Resources are created through the rollback helper so a failure never leaves partial state behind
+	createdObjs, err := createResourcesWithRollback(c.Request.Context(), k8sDyn, project, []resourceToCreate{
+		{GVR: gvr, Obj: obj},
+	}, createOpts)
+	if err != nil && len(createOpts.DryRun) > 0 {
+		// Surfacing admission and quota rejections is the point of a dry run
+		log.Printf("Dry-run create of agentic session in project %s rejected: %v", project, err)
+		c.JSON(ClassifyAPIError(err).HTTPStatus(), gin.H{"error": err.Error(), "dryRun": true})
+		return
+	}
 	if err != nil {
 		log.Printf("Failed to create agentic session in project %s: %v", project, err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create agentic session"})
 		return
 	}
+	created := createdObjs[0]
+
+	if len(createOpts.DryRun) > 0 {
+		c.JSON(http.StatusOK, gin.H{
+			"message":    "Agentic session validated (dry run, not created)",
+			"dryRun":     true,
+			"name":       name,
+			"autoBranch": ComputeAutoBranch(name),
+			"session":    created.Object,
+		})
+		return
+	}
 
 	// Best-effort prefill of agent markdown into PVC workspace for immediate UI availability
 	// Uses AGENT_PERSONAS or AGENT_PERSONA if provided in request environment variables
@@ -791,6 +1312,10 @@ func GetSession(c *gin.Context) {
 func MintSessionGitHubToken(c *gin.Context) {
 	project := c.Param("projectName")
 	sessionName := c.Param("sessionName")
+	if err := ValidateNamespace(project); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	rawAuth := strings.TrimSpace(c.GetHeader("Authorization"))
 	if rawAuth == "" {
@@ -1243,6 +1768,7 @@ func SelectWorkflow(c *gin.Context) {
 		if resp.StatusCode != http.StatusOK {
 			body, _ := io.ReadAll(resp.Body)
 			log.Printf("Runner failed to activate workflow (status %d): %s", resp.StatusCode, string(body))
+			ForwardRetryAfter(c.Writer, resp)
 			c.JSON(resp.StatusCode, gin.H{"error": fmt.Sprintf("Failed to activate workflow: %s", string(body))})
 			return
 		}
@@ -1379,6 +1905,7 @@ func AddRepo(c *gin.Context) {
 		if resp.StatusCode != http.StatusOK {
 			body, _ := io.ReadAll(resp.Body)
 			log.Printf("Runner failed to clone repo (status %d): %s", resp.StatusCode, string(body))
+			ForwardRetryAfter(c.Writer, resp)
 			c.JSON(resp.StatusCode, gin.H{"error": fmt.Sprintf("Failed to clone repository: %s", string(body))})
 			return
 		}
@@ -1641,6 +2168,7 @@ func GetWorkflowMetadata(c *gin.Context) {
 		log.Printf("GetWorkflowMetadata: content service returned error status %d: %s", resp.StatusCode, string(b))
 	}
 
+	ForwardRetryAfter(c.Writer, resp)
 	c.Data(resp.StatusCode, "application/json", b)
 }
 
@@ -1771,6 +2299,10 @@ func ListOOTBWorkflows(c *gin.Context) {
 	token := ""
 	project := c.Query("project") // Optional query parameter
 	if project != "" {
+		if err := ValidateNamespace(project); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		usrID, _ := c.Get("userID")
 		k8sClt, sessDyn := GetK8sClientsForRequest(c)
 		if k8sClt != nil && sessDyn != nil {
@@ -2233,6 +2765,123 @@ func StopSession(c *gin.Context) {
 	c.JSON(http.StatusAccepted, session)
 }
 
+// Cancellation annotations written by CancelSession
+const (
+	// cancelRequestedAtAnnotation records when a user cancelled the session
+	cancelRequestedAtAnnotation = "ambient-code.io/cancel-requested-at"
+	// cancelPodRetries bounds attempts to delete the runner pod
+	cancelPodRetries = 3
+)
+
+// cancelledPhases are phases in which CancelSession has nothing left to do
+var cancelledPhases = map[string]bool{
+	SessionPhaseStopping:  true,
+	SessionPhaseStopped:   true,
+	SessionPhaseCompleted: true,
+	SessionPhaseFailed:    true,
+}
+
+// CancelSession terminates a running session immediately
+// POST /api/projects/:projectName/agentic-sessions/:sessionName/cancel
+// Unlike StopSession, which leaves cleanup to the operator, it deletes the runner pod itself and
+// moves the session to Stopping; the operator then finishes the transition to Stopped
+// Returns 202 with the session once cancellation is under way, or 200 with the current state
+// when the session is already stopping or finished, so repeated calls are safe
+func CancelSession(c *gin.Context) {
+	project := c.GetString("project")
+	sessionName := c.Param("sessionName")
+	gvr := GetAgenticSessionV1Alpha1Resource()
+
+	k8sClt, k8sDyn := GetK8sClientsForRequest(c)
+	if k8sClt == nil || k8sDyn == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or missing token"})
+		c.Abort()
+		return
+	}
+	ctx := c.Request.Context()
+
+	allowed, _, _, err := checkResourceAccess(ctx, k8sClt, project, gvr.Group, gvr.Resource, sessionName, "update")
+	if err != nil {
+		log.Printf("CancelSession: access check for %s/%s failed: %v", project, sessionName, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to perform access review"})
+		return
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Unauthorized to cancel session"})
+		return
+	}
+
+	item, err := k8sDyn.Resource(gvr).Namespace(project).Get(ctx, sessionName, v1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+			return
+		}
+		log.Printf("Failed to get agentic session %s in project %s: %v", sessionName, project, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get agentic session"})
+		return
+	}
+
+	phase, _, _ := unstructured.NestedString(item.Object, "status", "phase")
+	if cancelledPhases[phase] {
+		c.JSON(http.StatusOK, sessionFromUnstructured(project, *item))
+		return
+	}
+
+	// Signal the operator first so it does not recreate the pod we are about to delete
+	annotations := item.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	now := time.Now().Format(time.RFC3339)
+	annotations["ambient-code.io/desired-phase"] = SessionPhaseStopped
+	annotations["ambient-code.io/stop-requested-at"] = now
+	annotations[cancelRequestedAtAnnotation] = now
+	item.SetAnnotations(annotations)
+	if _, err := k8sDyn.Resource(gvr).Namespace(project).Update(ctx, item, v1.UpdateOptions{}); err != nil {
+		if errors.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+			return
+		}
+		log.Printf("CancelSession: failed to annotate %s/%s: %v", project, sessionName, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cancel session"})
+		return
+	}
+
+	if err := NewSessionStatusClient(k8sDyn).SetPhase(ctx, project, sessionName, SessionPhaseStopping, "Cancelled by user"); err != nil {
+		log.Printf("CancelSession: failed to set phase for %s/%s: %v", project, sessionName, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cancel session"})
+		return
+	}
+
+	podName := fmt.Sprintf("%s-runner", sessionName)
+	err = RetryWithOptions(ctx, RetryOptions{
+		MaxRetries:   cancelPodRetries,
+		InitialDelay: 200 * time.Millisecond,
+		MaxDelay:     2 * time.Second,
+		Operation:    "cancel-session-pod",
+		Retryable:    func(err error) bool { return !errors.IsForbidden(err) },
+	}, func() error {
+		err := k8sClt.CoreV1().Pods(project).Delete(ctx, podName, v1.DeleteOptions{})
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		// The operator deletes the pod when it sees desired-phase=Stopped, so this is not fatal
+		log.Printf("CancelSession: failed to delete runner pod %s/%s, leaving it to the operator: %v", project, podName, err)
+	}
+
+	updated, err := k8sDyn.Resource(gvr).Namespace(project).Get(ctx, sessionName, v1.GetOptions{})
+	if err != nil {
+		log.Printf("CancelSession: failed to re-read %s/%s: %v", project, sessionName, err)
+		updated = item
+	}
+	log.Printf("CancelSession: cancelled session %s/%s", project, sessionName)
+	c.JSON(http.StatusAccepted, sessionFromUnstructured(project, *updated))
+}
+
 // GetSessionK8sResources returns job, pod, and PVC information for a session
 // GET /api/projects/:projectName/agentic-sessions/:sessionName/k8s-resources
 func GetSessionK8sResources(c *gin.Context) {
@@ -2440,6 +3089,7 @@ func ListSessionWorkspace(c *gin.Context) {
 		return
 	}
 
+	ForwardRetryAfter(c.Writer, resp)
 	c.Data(resp.StatusCode, resp.Header.Get("Content-Type"), b)
 }
 
@@ -2491,6 +3141,7 @@ func GetSessionWorkspaceFile(c *gin.Context) {
 	client := &http.Client{Timeout: 4 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
+		SetRetryAfter(c.Writer, DefaultRetryAfter)
 		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
 		return
 	}
@@ -2507,6 +3158,7 @@ func GetSessionWorkspaceFile(c *gin.Context) {
 		log.Printf("GetSessionWorkspaceFile: content service returned error status %d for path %s", resp.StatusCode, sub)
 	}
 
+	ForwardRetryAfter(c.Writer, resp)
 	c.Data(resp.StatusCode, resp.Header.Get("Content-Type"), b)
 }
 
@@ -2664,6 +3316,7 @@ func PutSessionWorkspaceFile(c *gin.Context) {
 	client := &http.Client{Timeout: 4 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
+		SetRetryAfter(c.Writer, DefaultRetryAfter)
 		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
 		return
 	}
@@ -2680,6 +3333,7 @@ func PutSessionWorkspaceFile(c *gin.Context) {
 		log.Printf("PutSessionWorkspaceFile: content service returned error status %d for path %s: %s", resp.StatusCode, sub, string(rb))
 	}
 
+	ForwardRetryAfter(c.Writer, resp)
 	c.Data(resp.StatusCode, resp.Header.Get("Content-Type"), rb)
 }
 
@@ -2801,6 +3455,7 @@ func DeleteSessionWorkspaceFile(c *gin.Context) {
 	client := &http.Client{Timeout: 4 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
+		SetRetryAfter(c.Writer, DefaultRetryAfter)
 		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
 		return
 	}
@@ -2819,8 +3474,10 @@ func DeleteSessionWorkspaceFile(c *gin.Context) {
 		// Try to parse error from content service, otherwise use generic message
 		var errResp map[string]interface{}
 		if err := json.Unmarshal(rb, &errResp); err == nil {
+			ForwardRetryAfter(c.Writer, resp)
 			c.JSON(resp.StatusCode, errResp)
 		} else {
+			ForwardRetryAfter(c.Writer, resp)
 			c.JSON(resp.StatusCode, gin.H{"error": "Failed to delete file"})
 		}
 	}
@@ -2989,6 +3646,7 @@ func PushSessionRepo(c *gin.Context) {
 			}
 			return s
 		}())
+		ForwardRetryAfter(c.Writer, resp)
 		c.Data(resp.StatusCode, "application/json", bodyBytes)
 		return
 	}
@@ -3067,6 +3725,7 @@ func AbandonSessionRepo(c *gin.Context) {
 	}
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		log.Printf("abandonSessionRepo: content returned status=%d body=%s", resp.StatusCode, string(bodyBytes))
+		ForwardRetryAfter(c.Writer, resp)
 		c.Data(resp.StatusCode, "application/json", bodyBytes)
 		return
 	}
@@ -3134,9 +3793,49 @@ func DiffSessionRepo(c *gin.Context) {
 		})
 		return
 	}
+	ForwardRetryAfter(c.Writer, resp)
 	c.Data(resp.StatusCode, resp.Header.Get("Content-Type"), bodyBytes)
 }
 
+// GetSessionRepos returns the session's stored repos in canonical form (see CanonicalRepo)
+// so the UI can show what will actually be cloned. Stored data is not modified.
+// GET /api/projects/:projectName/agentic-sessions/:sessionName/repos
+func GetSessionRepos(c *gin.Context) {
+	project := c.GetString("project")
+	if project == "" {
+		project = c.Param("projectName")
+	}
+	sessionName := c.Param("sessionName")
+
+	_, k8sDyn := GetK8sClientsForRequest(c)
+	if k8sDyn == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or missing token"})
+		c.Abort()
+		return
+	}
+
+	gvr := GetAgenticSessionV1Alpha1Resource()
+	item, err := k8sDyn.Resource(gvr).Namespace(project).Get(c.Request.Context(), sessionName, v1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+			return
+		}
+		log.Printf("%sFailed to get agentic session %s in project %s: %v", requestIDLogPrefix(c.Request.Context()), sessionName, project, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get agentic session"})
+		return
+	}
+
+	repos := []types.SimpleRepo{}
+	if spec, ok := item.Object["spec"].(map[string]interface{}); ok {
+		for _, r := range parseSpec(spec).Repos {
+			repos = append(repos, CanonicalRepo(r))
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"repos": repos})
+}
+
 // GetReposStatus returns current status of all repositories (branches, current branch, etc.)
 // GET /api/projects/:projectName/agentic-sessions/:sessionName/repos/status
 func GetReposStatus(c *gin.Context) {
@@ -3261,6 +3960,7 @@ func GetGitStatus(c *gin.Context) {
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
+		SetRetryAfter(c.Writer, DefaultRetryAfter)
 		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "content service unavailable"})
 		return
 	}
@@ -3272,6 +3972,7 @@ func GetGitStatus(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read response from content service"})
 		return
 	}
+	ForwardRetryAfter(c.Writer, resp)
 	c.Data(resp.StatusCode, resp.Header.Get("Content-Type"), bodyBytes)
 }
 
@@ -3349,6 +4050,7 @@ func ConfigureGitRemote(c *gin.Context) {
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
+		SetRetryAfter(c.Writer, DefaultRetryAfter)
 		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "content service unavailable"})
 		return
 	}
@@ -3391,6 +4093,7 @@ func ConfigureGitRemote(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read response from content service"})
 		return
 	}
+	ForwardRetryAfter(c.Writer, resp)
 	c.Data(resp.StatusCode, resp.Header.Get("Content-Type"), bodyBytes)
 }
 
@@ -3470,6 +4173,7 @@ func SynchronizeGit(c *gin.Context) {
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
+		SetRetryAfter(c.Writer, DefaultRetryAfter)
 		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "content service unavailable"})
 		return
 	}
@@ -3481,6 +4185,7 @@ func SynchronizeGit(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read response from content service"})
 		return
 	}
+	ForwardRetryAfter(c.Writer, resp)
 	c.Data(resp.StatusCode, resp.Header.Get("Content-Type"), bodyBytes)
 }
 
@@ -3535,6 +4240,7 @@ func GetGitMergeStatus(c *gin.Context) {
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
+		SetRetryAfter(c.Writer, DefaultRetryAfter)
 		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "content service unavailable"})
 		return
 	}
@@ -3546,6 +4252,7 @@ func GetGitMergeStatus(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read response from content service"})
 		return
 	}
+	ForwardRetryAfter(c.Writer, resp)
 	c.Data(resp.StatusCode, resp.Header.Get("Content-Type"), bodyBytes)
 }
 
@@ -3623,6 +4330,7 @@ func GitPullSession(c *gin.Context) {
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
+		SetRetryAfter(c.Writer, DefaultRetryAfter)
 		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "content service unavailable"})
 		return
 	}
@@ -3634,6 +4342,7 @@ func GitPullSession(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read response from content service"})
 		return
 	}
+	ForwardRetryAfter(c.Writer, resp)
 	c.Data(resp.StatusCode, resp.Header.Get("Content-Type"), bodyBytes)
 }
 
@@ -3716,6 +4425,7 @@ func GitPushSession(c *gin.Context) {
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
+		SetRetryAfter(c.Writer, DefaultRetryAfter)
 		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "content service unavailable"})
 		return
 	}
@@ -3727,6 +4437,7 @@ func GitPushSession(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read response from content service"})
 		return
 	}
+	ForwardRetryAfter(c.Writer, resp)
 	c.Data(resp.StatusCode, resp.Header.Get("Content-Type"), bodyBytes)
 }
 
@@ -3786,6 +4497,7 @@ func GitCreateBranchSession(c *gin.Context) {
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
+		SetRetryAfter(c.Writer, DefaultRetryAfter)
 		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "content service unavailable"})
 		return
 	}
@@ -3797,6 +4509,7 @@ func GitCreateBranchSession(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read response from content service"})
 		return
 	}
+	ForwardRetryAfter(c.Writer, resp)
 	c.Data(resp.StatusCode, resp.Header.Get("Content-Type"), bodyBytes)
 }
 
@@ -3837,6 +4550,7 @@ func GitListBranchesSession(c *gin.Context) {
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
+		SetRetryAfter(c.Writer, DefaultRetryAfter)
 		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "content service unavailable"})
 		return
 	}
@@ -3848,6 +4562,7 @@ func GitListBranchesSession(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read response from content service"})
 		return
 	}
+	ForwardRetryAfter(c.Writer, resp)
 	c.Data(resp.StatusCode, resp.Header.Get("Content-Type"), bodyBytes)
 }
 
@@ -6,9 +6,13 @@ import (
 	"ambient-code-backend/tests/config"
 	test_constants "ambient-code-backend/tests/constants"
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"ambient-code-backend/tests/logger"
@@ -18,6 +22,7 @@ import (
 	"github.com/gin-gonic/gin"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	authv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -25,6 +30,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	k8stesting "k8s.io/client-go/testing"
 )
 
 var _ = Describe("Sessions Handler", Label(test_constants.LabelUnit, test_constants.LabelHandlers, test_constants.LabelSessions), func() {
@@ -223,6 +229,123 @@ var _ = Describe("Sessions Handler", Label(test_constants.LabelUnit, test_consta
 
 				logger.Log("Filtered session list returned successfully")
 			})
+
+			It("Should stream the same sessions as NDJSON when requested", func() {
+				// Arrange: standard JSON response
+				context := httpUtils.CreateTestGinContext("GET", "/api/projects/"+testNamespace+"/agentic-sessions", nil)
+				httpUtils.SetAuthHeader(testToken)
+				httpUtils.SetProjectContext(testNamespace)
+				ListSessions(context)
+				httpUtils.AssertHTTPStatus(http.StatusOK)
+
+				var response struct {
+					Items []types.AgenticSession `json:"items"`
+				}
+				httpUtils.GetResponseJSON(&response)
+				jsonNames := make([]string, 0, len(response.Items))
+				for _, item := range response.Items {
+					jsonNames = append(jsonNames, item.Metadata["name"].(string))
+				}
+
+				// Act: NDJSON response
+				context = httpUtils.CreateTestGinContext("GET", "/api/projects/"+testNamespace+"/agentic-sessions", nil)
+				context.Request.Header.Set("Accept", "application/x-ndjson")
+				httpUtils.SetAuthHeader(testToken)
+				httpUtils.SetProjectContext(testNamespace)
+				ListSessions(context)
+
+				// Assert
+				httpUtils.AssertHTTPStatus(http.StatusOK)
+				recorder := httpUtils.GetResponseRecorder()
+				Expect(recorder.Header().Get("Content-Type")).To(Equal("application/x-ndjson"))
+				Expect(recorder.Flushed).To(BeTrue(), "NDJSON responses should be flushed as pages arrive")
+
+				lines := strings.Split(strings.TrimSpace(recorder.Body.String()), "\n")
+				ndjsonNames := make([]string, 0, len(lines))
+				for _, line := range lines {
+					var session types.AgenticSession
+					Expect(json.Unmarshal([]byte(line), &session)).To(Succeed())
+					ndjsonNames = append(ndjsonNames, session.Metadata["name"].(string))
+				}
+				Expect(ndjsonNames).To(ConsistOf(jsonNames))
+				Expect(ndjsonNames).To(HaveLen(2))
+			})
+		})
+
+		Context("When paging with pageToken", func() {
+			var pager *pagingDynamicClient
+
+			BeforeEach(func() {
+				for i := 1; i <= 3; i++ {
+					createTestSession(fmt.Sprintf("session-%d-%s", i, randomName), testNamespace, k8sUtils)
+				}
+				pager = &pagingDynamicClient{Interface: k8sUtils.DynamicClient}
+				DynamicClient = pager
+			})
+
+			list := func(query string) types.PaginatedResponse {
+				context := httpUtils.CreateTestGinContext("GET", "/api/projects/"+testNamespace+"/agentic-sessions?"+query, nil)
+				httpUtils.SetAuthHeader(testToken)
+				httpUtils.SetProjectContext(testNamespace)
+				ListSessions(context)
+
+				var response types.PaginatedResponse
+				if httpUtils.GetResponseRecorder().Code == http.StatusOK {
+					httpUtils.GetResponseJSON(&response)
+				}
+				return response
+			}
+
+			It("Should walk every session across two pages", func() {
+				first := list("pageToken=&limit=2")
+				httpUtils.AssertHTTPStatus(http.StatusOK)
+				Expect(first.Items).To(HaveLen(2))
+				Expect(first.HasMore).To(BeTrue())
+				Expect(first.NextPageToken).NotTo(BeEmpty())
+				Expect(pager.seen[0]).To(Equal(v1.ListOptions{Limit: 2}))
+
+				second := list("pageToken=" + first.NextPageToken + "&limit=2")
+				httpUtils.AssertHTTPStatus(http.StatusOK)
+				Expect(second.Items).To(HaveLen(1))
+				Expect(second.HasMore).To(BeFalse())
+				Expect(second.NextPageToken).To(BeEmpty())
+				Expect(pager.seen[1]).To(Equal(v1.ListOptions{Limit: 2, Continue: first.NextPageToken}))
+
+				var names []string
+				for _, page := range []types.PaginatedResponse{first, second} {
+					for _, item := range page.Items.([]interface{}) {
+						names = append(names, item.(map[string]interface{})["metadata"].(map[string]interface{})["name"].(string))
+					}
+				}
+				Expect(names).To(ConsistOf(
+					"session-1-"+randomName, "session-2-"+randomName, "session-3-"+randomName,
+				))
+			})
+
+			It("Should return 400 for a token the API server rejects", func() {
+				list("pageToken=not-a-token&limit=2")
+				httpUtils.AssertHTTPStatus(http.StatusBadRequest)
+				httpUtils.AssertJSONContains(map[string]interface{}{"error": "Invalid or expired pageToken"})
+			})
+
+			It("Should return 400 for an expired token", func() {
+				pager.expired = true
+				list("pageToken=" + pagingToken(2) + "&limit=2")
+				httpUtils.AssertHTTPStatus(http.StatusBadRequest)
+			})
+
+			DescribeTable("Should return 400 for an out-of-range limit",
+				func(query string) {
+					list(query)
+					httpUtils.AssertHTTPStatus(http.StatusBadRequest)
+					Expect(pager.seen).To(BeEmpty(), "invalid limits should be rejected before listing")
+				},
+				Entry("zero", "pageToken=&limit=0"),
+				Entry("negative", "pageToken=&limit=-5"),
+				Entry("too large", fmt.Sprintf("pageToken=&limit=%d", types.MaxPaginationLimit+1)),
+				Entry("not a number", "pageToken=&limit=ten"),
+				Entry("offset pagination too", "offset=0&limit=0"),
+			)
 		})
 
 		Context("When accessing a different project", func() {
@@ -292,6 +415,376 @@ var _ = Describe("Sessions Handler", Label(test_constants.LabelUnit, test_consta
 				logger.Log("Session created successfully: %s", sessionName)
 			})
 
+			It("Should apply project repo defaults to unset fields", func() {
+				settings := &unstructured.Unstructured{Object: map[string]interface{}{
+					"apiVersion": "vteam.ambient-code/v1alpha1",
+					"kind":       "ProjectSettings",
+					"metadata":   map[string]interface{}{"name": "projectsettings", "namespace": testNamespace},
+					"spec": map[string]interface{}{
+						"groupAccess":  []interface{}{},
+						"repoDefaults": map[string]interface{}{"autoPush": true, "cloneTimeout": "10m"},
+					},
+				}}
+				_, err := k8sUtils.DynamicClient.Resource(GetProjectSettingsResource()).Namespace(testNamespace).Create(ctx, settings, v1.CreateOptions{})
+				Expect(err).NotTo(HaveOccurred())
+
+				sessionRequest := map[string]interface{}{
+					"initialPrompt": "Test prompt",
+					"repos": []interface{}{
+						map[string]interface{}{"url": "https://github.com/test/defaulted.git"},
+						map[string]interface{}{"url": "https://github.com/test/explicit.git", "autoPush": false},
+					},
+				}
+
+				context := httpUtils.CreateTestGinContext("POST", "/api/projects/"+testNamespace+"/agentic-sessions", sessionRequest)
+				httpUtils.SetAuthHeader(testToken)
+				httpUtils.SetProjectContext(testNamespace)
+
+				CreateSession(context)
+
+				httpUtils.AssertHTTPStatus(http.StatusCreated)
+				var response map[string]interface{}
+				httpUtils.GetResponseJSON(&response)
+				created, err := k8sUtils.DynamicClient.Resource(sessionGVR).Namespace(testNamespace).Get(ctx, response["name"].(string), v1.GetOptions{})
+				Expect(err).NotTo(HaveOccurred())
+				repos, _, _ := unstructured.NestedSlice(created.Object, "spec", "repos")
+				Expect(repos).To(HaveLen(2))
+				Expect(repos[0]).To(HaveKeyWithValue("autoPush", true))
+				Expect(repos[0]).To(HaveKeyWithValue("cloneTimeout", "10m0s"))
+				Expect(repos[1]).To(HaveKeyWithValue("autoPush", false))
+			})
+
+			It("Should reject more than one primary repo", func() {
+				sessionRequest := map[string]interface{}{
+					"initialPrompt": "Test prompt",
+					"repos": []interface{}{
+						map[string]interface{}{"url": "https://github.com/owner/app.git", "primary": true},
+						map[string]interface{}{"url": "https://github.com/owner/lib.git", "primary": true},
+					},
+				}
+
+				context := httpUtils.CreateTestGinContext("POST", "/api/projects/"+testNamespace+"/agentic-sessions", sessionRequest)
+				httpUtils.SetAuthHeader(testToken)
+				httpUtils.SetProjectContext(testNamespace)
+
+				CreateSession(context)
+
+				httpUtils.AssertHTTPStatus(http.StatusBadRequest)
+				var response struct {
+					Errors []FieldError `json:"errors"`
+				}
+				httpUtils.GetResponseJSON(&response)
+				Expect(response.Errors).To(ConsistOf(FieldError{
+					Field:   "repos[1].primary",
+					Code:    RepoErrDuplicate,
+					Message: "primary is already set on repos[0]; at most one repo may be primary",
+				}))
+			})
+
+			It("Should report every validation error in one response", func() {
+				sessionRequest := map[string]interface{}{
+					"initialPrompt": "Test prompt",
+					"repos": []interface{}{
+						map[string]interface{}{"url": "https://github.com/owner/app.git", "primary": true, "dir": "src"},
+						map[string]interface{}{"url": "https://github.com/owner/lib.git", "primary": true, "dir": "../escape"},
+						map[string]interface{}{"url": "https://github.com/owner/docs.git", "dir": "src"},
+					},
+					"env":            []interface{}{map[string]interface{}{"name": "1BAD", "value": "x"}},
+					"sessionTimeout": "48h",
+				}
+
+				context := httpUtils.CreateTestGinContext("POST", "/api/projects/"+testNamespace+"/agentic-sessions?dryRun=maybe", sessionRequest)
+				httpUtils.SetAuthHeader(testToken)
+				httpUtils.SetProjectContext(testNamespace)
+
+				CreateSession(context)
+
+				httpUtils.AssertHTTPStatus(http.StatusBadRequest)
+				var response struct {
+					Error  string       `json:"error"`
+					Errors []FieldError `json:"errors"`
+				}
+				httpUtils.GetResponseJSON(&response)
+
+				fields := make([]string, len(response.Errors))
+				for i, e := range response.Errors {
+					fields[i] = e.Field
+					Expect(e.Code).NotTo(BeEmpty())
+					Expect(e.Message).NotTo(BeEmpty())
+				}
+				Expect(fields).To(Equal([]string{
+					"dryRun",
+					"repos[1].dir",
+					"repos[2].dir",
+					"repos[1].primary",
+					"env",
+					"sessionTimeout",
+				}))
+				Expect(response.Errors[2].Code).To(Equal(RepoErrDuplicate))
+				Expect(response.Errors[3].Code).To(Equal(RepoErrDuplicate))
+				Expect(response.Error).To(ContainSubstring("dryRun must be true or false"))
+				Expect(response.Error).To(ContainSubstring("sessionTimeout"))
+
+				list, err := k8sUtils.DynamicClient.Resource(sessionGVR).Namespace(testNamespace).List(ctx, v1.ListOptions{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(list.Items).To(BeEmpty(), "an invalid session must not be created")
+			})
+
+			It("Should report repo policy errors by index alongside session errors", func() {
+				RegisterRepoValidator(func(r types.SimpleRepo) error {
+					if strings.Contains(r.InputLocation().URL, "forbidden") {
+						return fmt.Errorf("forbidden org")
+					}
+					return nil
+				})
+				DeferCleanup(ResetRepoValidators)
+
+				sessionRequest := map[string]interface{}{
+					"initialPrompt": "Test prompt",
+					"repos": []interface{}{
+						map[string]interface{}{"url": "https://github.com/owner/app.git"},
+						map[string]interface{}{"url": "https://github.com/forbidden/lib.git"},
+					},
+					"sessionTimeout": "soon",
+				}
+
+				context := httpUtils.CreateTestGinContext("POST", "/api/projects/"+testNamespace+"/agentic-sessions", sessionRequest)
+				httpUtils.SetAuthHeader(testToken)
+				httpUtils.SetProjectContext(testNamespace)
+
+				CreateSession(context)
+
+				httpUtils.AssertHTTPStatus(http.StatusBadRequest)
+				var response struct {
+					Errors []FieldError `json:"errors"`
+				}
+				httpUtils.GetResponseJSON(&response)
+				Expect(response.Errors).To(HaveLen(2))
+				Expect(response.Errors[0]).To(Equal(FieldError{Field: "repos[1].repo", Code: RepoErrPolicy, Message: "forbidden org"}))
+				Expect(response.Errors[1].Field).To(Equal("sessionTimeout"))
+			})
+
+			It("Should parse every repo and report each invalid one by index", func() {
+				sessionRequest := map[string]interface{}{
+					"initialPrompt": "Test prompt",
+					"repos": []interface{}{
+						map[string]interface{}{"url": "https://github.com/owner/a.git", "readOnly": true, "output": map[string]interface{}{"url": "https://github.com/fork/a.git"}},
+						map[string]interface{}{"url": "https://github.com/owner/b", "output": map[string]interface{}{"url": "https://GitHub.com/owner/b.git"}},
+						map[string]interface{}{"url": "https://github.com/owner/c.git", "labels": map[string]interface{}{"bad key!": "x"}},
+						map[string]interface{}{"url": "https://github.com/owner/d.git", "priority": 999999},
+						map[string]interface{}{"url": "https://github.com/owner/e.git", "includePaths": []interface{}{"../../etc"}},
+						map[string]interface{}{"url": "https://github.com/owner/f.git", "cloneFilter": "bogus"},
+						map[string]interface{}{"url": "https://github.com/owner/g.git", "baseCommit": "not-a-sha"},
+						map[string]interface{}{"url": "https://github.com/owner/h.git", "cloneTimeout": "-5m"},
+						"https://github.com/owner/i.git",
+						map[string]interface{}{"url": "https://github.com/owner/ok.git", "autoPush": map[string]interface{}{"enabled": true}},
+					},
+					"sessionTimeout": "48h",
+				}
+
+				context := httpUtils.CreateTestGinContext("POST", "/api/projects/"+testNamespace+"/agentic-sessions", sessionRequest)
+				httpUtils.SetAuthHeader(testToken)
+				httpUtils.SetProjectContext(testNamespace)
+
+				CreateSession(context)
+
+				httpUtils.AssertHTTPStatus(http.StatusBadRequest)
+				var response struct {
+					Errors []FieldError `json:"errors"`
+				}
+				httpUtils.GetResponseJSON(&response)
+				fields := make([]string, len(response.Errors))
+				for i, e := range response.Errors {
+					fields[i] = e.Field + " " + e.Code
+				}
+				Expect(fields).To(Equal([]string{
+					"repos[0].readOnly " + RepoErrConflict,
+					"repos[1].output " + RepoErrSameAsInput,
+					"repos[2].labels[bad key!] " + RepoErrInvalidValue,
+					"repos[3].priority " + RepoErrInvalidValue,
+					"repos[4].includePaths[0] " + RepoErrInvalidPath,
+					"repos[5].cloneFilter " + RepoErrInvalidValue,
+					"repos[6].baseCommit " + RepoErrInvalidValue,
+					"repos[7].cloneTimeout " + RepoErrInvalidValue,
+					"repos[8].repo " + RepoErrInvalidType,
+					"sessionTimeout " + RepoErrInvalidValue,
+				}))
+
+				list, err := k8sUtils.DynamicClient.Resource(sessionGVR).Namespace(testNamespace).List(ctx, v1.ListOptions{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(list.Items).To(BeEmpty(), "an invalid session must not be created")
+			})
+
+			It("Should store readOnly only on repos without an output", func() {
+				sessionRequest := map[string]interface{}{
+					"initialPrompt": "Test prompt",
+					"repos": []interface{}{
+						map[string]interface{}{"url": "https://github.com/owner/docs.git", "readOnly": true},
+					},
+				}
+
+				context := httpUtils.CreateTestGinContext("POST", "/api/projects/"+testNamespace+"/agentic-sessions", sessionRequest)
+				httpUtils.SetAuthHeader(testToken)
+				httpUtils.SetProjectContext(testNamespace)
+
+				CreateSession(context)
+
+				httpUtils.AssertHTTPStatus(http.StatusCreated)
+				var response map[string]interface{}
+				httpUtils.GetResponseJSON(&response)
+				created, err := k8sUtils.DynamicClient.Resource(sessionGVR).Namespace(testNamespace).Get(ctx, response["name"].(string), v1.GetOptions{})
+				Expect(err).NotTo(HaveOccurred())
+				repos, _, _ := unstructured.NestedSlice(created.Object, "spec", "repos")
+				Expect(repos).To(ConsistOf(HaveKeyWithValue("readOnly", true)))
+
+				httpUtils = test_utils.NewHTTPTestUtils()
+				sessionRequest["repos"] = []interface{}{
+					map[string]interface{}{
+						"url":      "https://github.com/owner/docs.git",
+						"readOnly": true,
+						"output":   map[string]interface{}{"url": "https://github.com/fork/docs.git", "branch": "notes"},
+					},
+				}
+				context = httpUtils.CreateTestGinContext("POST", "/api/projects/"+testNamespace+"/agentic-sessions", sessionRequest)
+				httpUtils.SetAuthHeader(testToken)
+				httpUtils.SetProjectContext(testNamespace)
+
+				CreateSession(context)
+
+				httpUtils.AssertHTTPStatus(http.StatusBadRequest)
+				var rejected struct {
+					Errors []FieldError `json:"errors"`
+				}
+				httpUtils.GetResponseJSON(&rejected)
+				Expect(rejected.Errors).To(ConsistOf(FieldError{
+					Field:   "repos[0].readOnly",
+					Code:    RepoErrConflict,
+					Message: "readOnly repos cannot specify an output",
+				}))
+			})
+
+			It("Should reject an output that only respells the input", func() {
+				sessionRequest := map[string]interface{}{
+					"initialPrompt": "Test prompt",
+					"repos": []interface{}{
+						map[string]interface{}{"url": "https://github.com/owner/app.git", "output": map[string]interface{}{"url": "https://github.com/fork/app.git"}},
+						map[string]interface{}{"url": "https://github.com/o/r", "output": map[string]interface{}{"url": "https://GitHub.com/o/r.git"}},
+						map[string]interface{}{"url": "git@github.com:o/s.git", "branch": "main", "output": map[string]interface{}{"url": "https://github.com/O/S/", "branch": "main"}},
+					},
+				}
+
+				context := httpUtils.CreateTestGinContext("POST", "/api/projects/"+testNamespace+"/agentic-sessions", sessionRequest)
+				httpUtils.SetAuthHeader(testToken)
+				httpUtils.SetProjectContext(testNamespace)
+
+				CreateSession(context)
+
+				httpUtils.AssertHTTPStatus(http.StatusBadRequest)
+				var response struct {
+					Errors []FieldError `json:"errors"`
+				}
+				httpUtils.GetResponseJSON(&response)
+				Expect(response.Errors).To(HaveLen(2))
+				for i, e := range response.Errors {
+					Expect(e.Field).To(Equal(fmt.Sprintf("repos[%d].output", i+1)))
+					Expect(e.Code).To(Equal(RepoErrSameAsInput))
+				}
+			})
+
+			It("Should store the fork url derived from forkOwner", func() {
+				sessionRequest := map[string]interface{}{
+					"initialPrompt": "Test prompt",
+					"repos": []interface{}{
+						map[string]interface{}{
+							"url":    "https://github.com/upstream/app.git",
+							"branch": "main",
+							"output": map[string]interface{}{"forkOwner": "me"},
+						},
+					},
+				}
+
+				context := httpUtils.CreateTestGinContext("POST", "/api/projects/"+testNamespace+"/agentic-sessions", sessionRequest)
+				httpUtils.SetAuthHeader(testToken)
+				httpUtils.SetProjectContext(testNamespace)
+
+				CreateSession(context)
+
+				httpUtils.AssertHTTPStatus(http.StatusCreated)
+				var response map[string]interface{}
+				httpUtils.GetResponseJSON(&response)
+				created, err := k8sUtils.DynamicClient.Resource(sessionGVR).Namespace(testNamespace).Get(ctx, response["name"].(string), v1.GetOptions{})
+				Expect(err).NotTo(HaveOccurred())
+				repos, _, _ := unstructured.NestedSlice(created.Object, "spec", "repos")
+				Expect(repos).To(HaveLen(1))
+				Expect(repos[0]).To(HaveKeyWithValue("output", map[string]interface{}{
+					"url":    "https://github.com/me/app",
+					"branch": "main",
+				}))
+			})
+
+			It("Should store valid repo labels and reject invalid ones", func() {
+				sessionRequest := map[string]interface{}{
+					"initialPrompt": "Test prompt",
+					"repos": []interface{}{
+						map[string]interface{}{"url": "https://github.com/owner/app.git", "labels": map[string]interface{}{"team": "infra", "example.com/component": "app"}},
+					},
+				}
+
+				context := httpUtils.CreateTestGinContext("POST", "/api/projects/"+testNamespace+"/agentic-sessions", sessionRequest)
+				httpUtils.SetAuthHeader(testToken)
+				httpUtils.SetProjectContext(testNamespace)
+
+				CreateSession(context)
+
+				httpUtils.AssertHTTPStatus(http.StatusCreated)
+				var response map[string]interface{}
+				httpUtils.GetResponseJSON(&response)
+				created, err := k8sUtils.DynamicClient.Resource(sessionGVR).Namespace(testNamespace).Get(ctx, response["name"].(string), v1.GetOptions{})
+				Expect(err).NotTo(HaveOccurred())
+				repos, _, _ := unstructured.NestedSlice(created.Object, "spec", "repos")
+				Expect(repos).To(ConsistOf(HaveKeyWithValue("labels", map[string]interface{}{"team": "infra", "example.com/component": "app"})))
+
+				httpUtils = test_utils.NewHTTPTestUtils()
+				sessionRequest["repos"] = []interface{}{
+					map[string]interface{}{"url": "https://github.com/owner/app.git", "labels": map[string]interface{}{"team": "infra"}},
+					map[string]interface{}{"url": "https://github.com/owner/lib.git", "labels": map[string]interface{}{"bad key!": "x"}},
+				}
+				context = httpUtils.CreateTestGinContext("POST", "/api/projects/"+testNamespace+"/agentic-sessions", sessionRequest)
+				httpUtils.SetAuthHeader(testToken)
+				httpUtils.SetProjectContext(testNamespace)
+
+				CreateSession(context)
+
+				httpUtils.AssertHTTPStatus(http.StatusBadRequest)
+				var rejected struct {
+					Errors []FieldError `json:"errors"`
+				}
+				httpUtils.GetResponseJSON(&rejected)
+				Expect(rejected.Errors).To(HaveLen(1))
+				Expect(rejected.Errors[0].Field).To(Equal("repos[1].labels[bad key!]"))
+				Expect(rejected.Errors[0].Code).To(Equal(RepoErrInvalidValue))
+			})
+
+			It("Should record the session timeout as an annotation", func() {
+				sessionRequest := map[string]interface{}{
+					"initialPrompt":  "Test prompt",
+					"sessionTimeout": "90m",
+				}
+
+				context := httpUtils.CreateTestGinContext("POST", "/api/projects/"+testNamespace+"/agentic-sessions", sessionRequest)
+				httpUtils.SetAuthHeader(testToken)
+				httpUtils.SetProjectContext(testNamespace)
+
+				CreateSession(context)
+
+				httpUtils.AssertHTTPStatus(http.StatusCreated)
+				var response map[string]interface{}
+				httpUtils.GetResponseJSON(&response)
+				created, err := k8sUtils.DynamicClient.Resource(sessionGVR).Namespace(testNamespace).Get(ctx, response["name"].(string), v1.GetOptions{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(created.GetAnnotations()).To(HaveKeyWithValue(sessionTimeoutAnnotation, "1h30m0s"))
+			})
+
 			It("Should generate unique session names", func() {
 				sessionRequest := map[string]interface{}{
 					"initialPrompt": "Test prompt",
@@ -407,6 +900,56 @@ var _ = Describe("Sessions Handler", Label(test_constants.LabelUnit, test_consta
 				// Assert - handler currently accepts invalid URLs (validation at runtime)
 				httpUtils.AssertHTTPStatus(http.StatusCreated)
 			})
+
+			It("Should validate without persisting on dry run", func() {
+				sessionRequest := map[string]interface{}{
+					"initialPrompt": "Test prompt",
+					"repos": []interface{}{
+						map[string]interface{}{"url": "https://github.com/test/repo.git"},
+					},
+				}
+
+				context := httpUtils.CreateTestGinContext("POST", "/api/projects/"+testNamespace+"/agentic-sessions?dryRun=true", sessionRequest)
+				httpUtils.SetAuthHeader(testToken)
+				httpUtils.SetProjectContext(testNamespace)
+
+				CreateSession(context)
+
+				httpUtils.AssertHTTPStatus(http.StatusOK)
+				var response map[string]interface{}
+				httpUtils.GetResponseJSON(&response)
+				Expect(response).To(HaveKeyWithValue("dryRun", true))
+				Expect(response).To(HaveKeyWithValue("session", HaveKeyWithValue("spec", HaveKey("repos"))))
+
+				list, err := k8sUtils.DynamicClient.Resource(sessionGVR).Namespace(testNamespace).List(ctx, v1.ListOptions{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(list.Items).To(BeEmpty(), "dry run must not persist the session")
+			})
+
+			It("Should reject an invalid dryRun value", func() {
+				context := httpUtils.CreateTestGinContext("POST", "/api/projects/"+testNamespace+"/agentic-sessions?dryRun=maybe", map[string]interface{}{"initialPrompt": "Test prompt"})
+				httpUtils.SetAuthHeader(testToken)
+				httpUtils.SetProjectContext(testNamespace)
+
+				CreateSession(context)
+
+				httpUtils.AssertHTTPStatus(http.StatusBadRequest)
+			})
+
+			It("Should reject a session timeout over the maximum", func() {
+				sessionRequest := map[string]interface{}{
+					"initialPrompt":  "Test prompt",
+					"sessionTimeout": "48h",
+				}
+
+				context := httpUtils.CreateTestGinContext("POST", "/api/projects/"+testNamespace+"/agentic-sessions", sessionRequest)
+				httpUtils.SetAuthHeader(testToken)
+				httpUtils.SetProjectContext(testNamespace)
+
+				CreateSession(context)
+
+				httpUtils.AssertHTTPStatus(http.StatusBadRequest)
+			})
 		})
 	})
 
@@ -472,6 +1015,99 @@ var _ = Describe("Sessions Handler", Label(test_constants.LabelUnit, test_consta
 		})
 	})
 
+	Describe("GetSessionRepos", func() {
+		var (
+			sessionName string
+			messyRepos  []interface{}
+		)
+
+		BeforeEach(func() {
+			sessionName = testSession
+			messyRepos = []interface{}{
+				map[string]interface{}{
+					"url":    "  git@GitHub.com:Owner/Repo.git ",
+					"branch": "  feature/x  ",
+				},
+				map[string]interface{}{
+					"url":      "http://github.com/owner/upstream/",
+					"branch":   "   ",
+					"autoPush": true,
+					"output": map[string]interface{}{
+						"url":    "https://github.com/me/Upstream.git",
+						"branch": " work ",
+					},
+				},
+			}
+
+			session := createTestSession(sessionName, testNamespace, k8sUtils)
+			Expect(unstructured.SetNestedSlice(session.Object, messyRepos, "spec", "repos")).To(Succeed())
+			_, err := k8sUtils.DynamicClient.Resource(sessionGVR).Namespace(testNamespace).Update(ctx, session, v1.UpdateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		getRepos := func(name string) {
+			path := fmt.Sprintf("/api/projects/%s/agentic-sessions/%s/repos", testNamespace, name)
+			context := httpUtils.CreateTestGinContext("GET", path, nil)
+			httpUtils.SetAuthHeader(testToken)
+			httpUtils.SetProjectContext(testNamespace)
+			context.Params = gin.Params{
+				{Key: "sessionName", Value: name},
+			}
+			GetSessionRepos(context)
+		}
+
+		It("Should return repos in canonical form", func() {
+			getRepos(sessionName)
+
+			httpUtils.AssertHTTPStatus(http.StatusOK)
+
+			var response struct {
+				Repos []types.SimpleRepo `json:"repos"`
+			}
+			httpUtils.GetResponseJSON(&response)
+			Expect(response.Repos).To(HaveLen(2))
+
+			first := response.Repos[0]
+			Expect(first.URL).To(Equal("https://github.com/owner/repo"))
+			Expect(first.Branch).NotTo(BeNil())
+			Expect(*first.Branch).To(Equal("feature/x"))
+			Expect(first.Input).NotTo(BeNil())
+			Expect(first.Input.URL).To(Equal("https://github.com/owner/repo"))
+			Expect(first.AutoPush).NotTo(BeNil())
+			Expect(*first.AutoPush).To(BeFalse(), "unset autoPush should resolve to its default")
+			Expect(first.ReadOnly).NotTo(BeNil())
+			Expect(*first.ReadOnly).To(BeFalse())
+			Expect(first.Output).To(BeNil())
+
+			second := response.Repos[1]
+			Expect(second.URL).To(Equal("https://github.com/owner/upstream"))
+			Expect(second.Branch).To(BeNil(), "blank branch should be unset")
+			Expect(*second.AutoPush).To(BeTrue())
+			Expect(second.Output).NotTo(BeNil())
+			Expect(second.Output.URL).To(Equal("https://github.com/me/upstream"))
+			Expect(*second.Output.Branch).To(Equal("work"))
+		})
+
+		It("Should not modify the stored repos", func() {
+			getRepos(sessionName)
+			httpUtils.AssertHTTPStatus(http.StatusOK)
+
+			stored, err := k8sUtils.DynamicClient.Resource(sessionGVR).Namespace(testNamespace).Get(ctx, sessionName, v1.GetOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			repos, found, err := unstructured.NestedSlice(stored.Object, "spec", "repos")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(repos).To(Equal(messyRepos))
+		})
+
+		It("Should return 404 for a missing session", func() {
+			getRepos("non-existent-session")
+
+			httpUtils.AssertHTTPStatus(http.StatusNotFound)
+			httpUtils.AssertErrorMessage("Session not found")
+		})
+	})
+
 	Describe("DeleteSession", func() {
 		var sessionName string
 
@@ -524,6 +1160,180 @@ var _ = Describe("Sessions Handler", Label(test_constants.LabelUnit, test_consta
 		})
 	})
 
+	Describe("CancelSession", func() {
+		var sessionName string
+
+		cancel := func(name string) {
+			path := fmt.Sprintf("/api/projects/%s/agentic-sessions/%s/cancel", testNamespace, name)
+			context := httpUtils.CreateTestGinContext("POST", path, nil)
+			httpUtils.SetAuthHeader(testToken)
+			httpUtils.SetProjectContext(testNamespace)
+			context.Params = gin.Params{
+				{Key: "sessionName", Value: name},
+			}
+			CancelSession(context)
+		}
+
+		BeforeEach(func() {
+			sessionName = "test-session-to-cancel"
+			createTestSession(sessionName, testNamespace, k8sUtils)
+
+			_, err := k8sUtils.K8sClient.CoreV1().Pods(testNamespace).Create(ctx, &corev1.Pod{
+				ObjectMeta: v1.ObjectMeta{Name: sessionName + "-runner", Namespace: testNamespace},
+			}, v1.CreateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("When session is active", func() {
+			It("Should mark the session stopping and delete the runner pod", func() {
+				// Act
+				cancel(sessionName)
+
+				// Assert
+				httpUtils.AssertHTTPStatus(http.StatusAccepted)
+
+				obj, err := k8sUtils.DynamicClient.Resource(sessionGVR).Namespace(testNamespace).Get(ctx, sessionName, v1.GetOptions{})
+				Expect(err).NotTo(HaveOccurred())
+				annotations := obj.GetAnnotations()
+				Expect(annotations).To(HaveKeyWithValue("ambient-code.io/desired-phase", "Stopped"))
+				Expect(annotations).To(HaveKey(cancelRequestedAtAnnotation))
+				phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+				Expect(phase).To(Equal(SessionPhaseStopping))
+
+				_, err = k8sUtils.K8sClient.CoreV1().Pods(testNamespace).Get(ctx, sessionName+"-runner", v1.GetOptions{})
+				Expect(errors.IsNotFound(err)).To(BeTrue(), "runner pod should be deleted")
+
+				logger.Log("Session cancelled successfully: %s", sessionName)
+			})
+		})
+
+		Context("When session is already cancelled", func() {
+			It("Should return 200 with the current state", func() {
+				// Arrange
+				cancel(sessionName)
+				httpUtils.AssertHTTPStatus(http.StatusAccepted)
+				httpUtils = test_utils.NewHTTPTestUtils()
+
+				// Act
+				cancel(sessionName)
+
+				// Assert
+				httpUtils.AssertHTTPStatus(http.StatusOK)
+
+				var response types.AgenticSession
+				httpUtils.GetResponseJSON(&response)
+				Expect(response.Status).NotTo(BeNil())
+				Expect(response.Status.Phase).To(Equal(SessionPhaseStopping))
+			})
+		})
+
+		Context("When session does not exist", func() {
+			It("Should return 404 Not Found", func() {
+				// Act
+				cancel("non-existent-session")
+
+				// Assert
+				httpUtils.AssertHTTPStatus(http.StatusNotFound)
+				httpUtils.AssertErrorMessage("Session not found")
+			})
+		})
+
+		Context("When the caller cannot update sessions", func() {
+			It("Should return 403 and leave the session untouched", func() {
+				// Arrange
+				k8sUtils.SSARAllowedFunc = func(action k8stesting.Action) bool { return false }
+				defer func() { k8sUtils.SSARAllowedFunc = nil }()
+
+				// Act
+				cancel(sessionName)
+
+				// Assert
+				httpUtils.AssertHTTPStatus(http.StatusForbidden)
+
+				obj, err := k8sUtils.DynamicClient.Resource(sessionGVR).Namespace(testNamespace).Get(ctx, sessionName, v1.GetOptions{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(obj.GetAnnotations()).NotTo(HaveKey(cancelRequestedAtAnnotation))
+			})
+		})
+	})
+
+	Describe("GetSessionPermissions", func() {
+		var (
+			reviewed    []string
+			deniedVerbs map[string]bool
+		)
+
+		getPermissions := func() map[string]bool {
+			path := fmt.Sprintf("/api/projects/%s/agentic-sessions/%s/permissions", testNamespace, testSession)
+			context := httpUtils.CreateTestGinContext("GET", path, nil)
+			httpUtils.SetAuthHeader(testToken)
+			httpUtils.SetProjectContext(testNamespace)
+			context.Params = gin.Params{
+				{Key: "sessionName", Value: testSession},
+			}
+			GetSessionPermissions(context)
+
+			httpUtils.AssertHTTPStatus(http.StatusOK)
+			var permissions map[string]bool
+			httpUtils.GetResponseJSON(&permissions)
+			return permissions
+		}
+
+		BeforeEach(func() {
+			reviewed = nil
+			deniedVerbs = map[string]bool{}
+			var mu sync.Mutex
+			k8sUtils.SSARAllowedFunc = func(action k8stesting.Action) bool {
+				attrs := action.(k8stesting.CreateAction).GetObject().(*authv1.SelfSubjectAccessReview).Spec.ResourceAttributes
+				if attrs.Resource != "agenticsessions" {
+					return true
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				reviewed = append(reviewed, attrs.Verb+":"+attrs.Name)
+				return !deniedVerbs[attrs.Verb]
+			}
+			DeferCleanup(func() { k8sUtils.SSARAllowedFunc = nil })
+		})
+
+		It("Should allow every action when every check passes", func() {
+			Expect(getPermissions()).To(Equal(map[string]bool{
+				"view": true, "update": true, "start": true, "stop": true, "cancel": true, "delete": true, "clone": true,
+			}))
+		})
+
+		It("Should report mixed allow and deny per action", func() {
+			deniedVerbs["delete"] = true
+			deniedVerbs["update"] = true
+
+			Expect(getPermissions()).To(Equal(map[string]bool{
+				"view": true, "update": false, "start": false, "stop": false, "cancel": false, "delete": false, "clone": true,
+			}))
+		})
+
+		It("Should run one review per distinct verb", func() {
+			getPermissions()
+
+			Expect(reviewed).To(ConsistOf(
+				"get:"+testSession,
+				"update:"+testSession,
+				"delete:"+testSession,
+				"create:",
+			))
+		})
+
+		It("Should require a token", func() {
+			path := fmt.Sprintf("/api/projects/%s/agentic-sessions/%s/permissions", testNamespace, testSession)
+			context := httpUtils.CreateTestGinContext("GET", path, nil)
+			httpUtils.SetProjectContext(testNamespace)
+			context.Params = gin.Params{{Key: "sessionName", Value: testSession}}
+
+			GetSessionPermissions(context)
+
+			httpUtils.AssertHTTPStatus(http.StatusUnauthorized)
+		})
+	})
+
 	// AutoPush functionality tests
 	Context("AutoPush Field Parsing", func() {
 		var (
@@ -777,6 +1587,211 @@ var _ = Describe("Sessions Handler", Label(test_constants.LabelUnit, test_consta
 				// Invalid type should be skipped, leaving AutoPush as nil
 				Expect(parsed.Repos[0].AutoPush).To(BeNil())
 			})
+
+			It("Should mark pull request refs on the input location", func() {
+				spec := map[string]interface{}{
+					"repos": []interface{}{
+						map[string]interface{}{
+							"url":         "https://github.com/owner/repo.git",
+							"branch":      "refs/pull/42/head",
+							"pullRequest": true,
+						},
+					},
+				}
+
+				parsed := parseSpec(spec)
+				Expect(parsed.Repos).To(HaveLen(1))
+				input := parsed.Repos[0].InputLocation()
+				Expect(input.PullRequest).To(BeTrue())
+				Expect(*input.Branch).To(Equal("refs/pull/42/head"))
+			})
+
+			It("Should read branch fallbacks onto the input location in order", func() {
+				spec := map[string]interface{}{
+					"repos": []interface{}{
+						map[string]interface{}{
+							"url":             "https://github.com/owner/repo.git",
+							"branchFallbacks": []interface{}{"release", "main"},
+						},
+					},
+				}
+
+				parsed := parseSpec(spec)
+				Expect(parsed.Repos).To(HaveLen(1))
+				input := parsed.Repos[0].InputLocation()
+				Expect(input.BranchFallbacks).To(Equal([]string{"release", "main"}))
+				Expect(input.CandidateBranches()).To(Equal([]string{"release", "main"}))
+			})
+
+			It("Should read repo labels", func() {
+				spec := map[string]interface{}{
+					"repos": []interface{}{
+						map[string]interface{}{
+							"url":    "https://github.com/owner/repo.git",
+							"labels": map[string]interface{}{"team": "platform", "bogus": 7},
+						},
+					},
+				}
+
+				parsed := parseSpec(spec)
+				Expect(parsed.Repos).To(HaveLen(1))
+				Expect(parsed.Repos[0].Labels).To(Equal(map[string]string{"team": "platform"}))
+			})
+
+			It("Should read repo path filters", func() {
+				spec := map[string]interface{}{
+					"repos": []interface{}{
+						map[string]interface{}{
+							"url":          "https://github.com/owner/repo.git",
+							"includePaths": []interface{}{"services/**", 7, " "},
+							"excludePaths": []interface{}{"services/legacy/**"},
+						},
+					},
+				}
+
+				parsed := parseSpec(spec)
+				Expect(parsed.Repos).To(HaveLen(1))
+				Expect(parsed.Repos[0].IncludePaths).To(Equal([]string{"services/**"}))
+				Expect(parsed.Repos[0].ExcludePaths).To(Equal([]string{"services/legacy/**"}))
+			})
+
+			It("Should read baseCommit onto the input location", func() {
+				spec := map[string]interface{}{
+					"repos": []interface{}{
+						map[string]interface{}{
+							"url":        "https://github.com/owner/repo.git",
+							"branch":     "main",
+							"baseCommit": "0123456789abcdef0123456789abcdef01234567",
+						},
+					},
+				}
+
+				parsed := parseSpec(spec)
+				Expect(parsed.Repos).To(HaveLen(1))
+				input := parsed.Repos[0].InputLocation()
+				Expect(*input.BaseCommit).To(Equal("0123456789abcdef0123456789abcdef01234567"))
+				Expect(*input.Branch).To(Equal("main"))
+			})
+
+			It("Should read cloneFilter and skip unknown values", func() {
+				spec := map[string]interface{}{
+					"repos": []interface{}{
+						map[string]interface{}{"url": "https://github.com/owner/huge.git", "cloneFilter": "blobless"},
+						map[string]interface{}{"url": "https://github.com/owner/other.git", "cloneFilter": "shallow"},
+					},
+				}
+
+				parsed := parseSpec(spec)
+				Expect(parsed.Repos).To(HaveLen(2))
+				Expect(parsed.Repos[0].CloneFilter).To(Equal(types.StringPtr("blobless")))
+				Expect(parsed.Repos[1].CloneFilter).To(BeNil())
+			})
+
+			It("Should read primary", func() {
+				spec := map[string]interface{}{
+					"repos": []interface{}{
+						map[string]interface{}{"url": "https://github.com/owner/lib.git"},
+						map[string]interface{}{"url": "https://github.com/owner/app.git", "primary": true},
+					},
+				}
+
+				parsed := parseSpec(spec)
+				Expect(parsed.Repos[0].Primary).To(BeNil())
+				Expect(parsed.Repos[1].Primary).To(Equal(types.BoolPtr(true)))
+				_, i := PrimaryRepo(parsed.Repos)
+				Expect(i).To(Equal(1))
+			})
+
+			It("Should read depth and skip values below 1", func() {
+				spec := map[string]interface{}{
+					"repos": []interface{}{
+						map[string]interface{}{"url": "https://github.com/owner/huge.git", "depth": int64(10)},
+						map[string]interface{}{"url": "https://github.com/owner/other.git", "depth": int64(0)},
+					},
+				}
+
+				parsed := parseSpec(spec)
+				Expect(parsed.Repos).To(HaveLen(2))
+				Expect(parsed.Repos[0].Depth).To(Equal(types.IntPtr(10)))
+				Expect(parsed.Repos[1].Depth).To(BeNil())
+			})
+
+			It("Should read retries and skip out-of-range values", func() {
+				spec := map[string]interface{}{
+					"repos": []interface{}{
+						map[string]interface{}{"url": "https://github.com/owner/flaky.git", "retries": map[string]interface{}{"maxAttempts": int64(5), "initialDelayMs": int64(200)}},
+						map[string]interface{}{"url": "https://github.com/owner/other.git", "retries": map[string]interface{}{"maxAttempts": int64(50), "initialDelayMs": int64(100)}},
+						map[string]interface{}{"url": "https://github.com/owner/third.git", "retries": map[string]interface{}{"maxAttempts": int64(0)}},
+					},
+				}
+
+				parsed := parseSpec(spec)
+				Expect(parsed.Repos).To(HaveLen(3))
+				Expect(parsed.Repos[0].Retries).To(Equal(&types.RetryConfig{MaxAttempts: types.IntPtr(5), InitialDelayMs: types.IntPtr(200)}))
+				Expect(parsed.Repos[1].Retries).To(Equal(&types.RetryConfig{InitialDelayMs: types.IntPtr(100)}))
+				Expect(parsed.Repos[2].Retries).To(BeNil())
+			})
+
+			It("Should read credentialsSecretRef on the input and output locations", func() {
+				spec := map[string]interface{}{
+					"repos": []interface{}{
+						map[string]interface{}{
+							"url":                  "https://github.com/upstream/repo.git",
+							"credentialsSecretRef": map[string]interface{}{"name": "github-read", "key": "token"},
+							"output": map[string]interface{}{
+								"url":                  "https://github.com/fork/repo.git",
+								"credentialsSecretRef": map[string]interface{}{"name": "github-push", "key": "token"},
+							},
+						},
+					},
+				}
+
+				parsed := parseSpec(spec)
+				Expect(parsed.Repos).To(HaveLen(1))
+				Expect(parsed.Repos[0].InputCredentials()).To(Equal(&types.SecretRef{Name: "github-read", Key: "token"}))
+				Expect(parsed.Repos[0].OutputCredentials()).To(Equal(&types.SecretRef{Name: "github-push", Key: "token"}))
+			})
+
+			It("Should read autoPush options alongside autoPush", func() {
+				spec := map[string]interface{}{
+					"repos": []interface{}{
+						map[string]interface{}{
+							"url":             "https://github.com/owner/repo.git",
+							"autoPush":        true,
+							"autoPushOptions": map[string]interface{}{"squash": true, "intervalSeconds": int64(600)},
+						},
+					},
+				}
+
+				parsed := parseSpec(spec)
+				Expect(parsed.Repos).To(HaveLen(1))
+				Expect(parsed.Repos[0].AutoPushOptions).To(Equal(&types.AutoPushSpec{
+					Enabled:         types.BoolPtr(true),
+					Squash:          types.BoolPtr(true),
+					IntervalSeconds: types.IntPtr(600),
+				}))
+			})
+
+			It("Should read a stored push condition", func() {
+				spec := map[string]interface{}{
+					"repos": []interface{}{
+						map[string]interface{}{
+							"url":      "https://github.com/owner/repo.git",
+							"autoPush": true,
+							"autoPushOptions": map[string]interface{}{
+								"pushCondition": pushConditionMap(&types.PushCondition{Command: "make test", SuccessExitCodes: []int{0, 3}}),
+							},
+						},
+					},
+				}
+
+				parsed := parseSpec(spec)
+				Expect(parsed.Repos).To(HaveLen(1))
+				Expect(parsed.Repos[0].AutoPushOptions).To(Equal(&types.AutoPushSpec{
+					Enabled:       types.BoolPtr(true),
+					PushCondition: &types.PushCondition{Command: "make test", SuccessExitCodes: []int{0, 3}},
+				}))
+			})
 		})
 	})
 })
@@ -829,3 +1844,66 @@ func createTestSession(name, namespace string, k8sUtils *test_utils.K8sTestUtils
 	}
 	return created
 }
+
+// pagingDynamicClient pages agentic session lists the way the API server does: it honors
+// Limit and Continue, hands out opaque continue tokens and rejects ones it did not issue
+// The fake dynamic client ignores both options, so handler pagination cannot be tested without it
+type pagingDynamicClient struct {
+	dynamic.Interface
+	seen    []v1.ListOptions
+	expired bool
+}
+
+func (p *pagingDynamicClient) Resource(gvr schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	return pagingResource{NamespaceableResourceInterface: p.Interface.Resource(gvr), pager: p}
+}
+
+type pagingResource struct {
+	dynamic.NamespaceableResourceInterface
+	pager *pagingDynamicClient
+}
+
+func (r pagingResource) Namespace(ns string) dynamic.ResourceInterface {
+	return pagingNamespacedResource{ResourceInterface: r.NamespaceableResourceInterface.Namespace(ns), pager: r.pager}
+}
+
+type pagingNamespacedResource struct {
+	dynamic.ResourceInterface
+	pager *pagingDynamicClient
+}
+
+func (r pagingNamespacedResource) List(ctx context.Context, opts v1.ListOptions) (*unstructured.UnstructuredList, error) {
+	r.pager.seen = append(r.pager.seen, opts)
+
+	start := 0
+	if opts.Continue != "" {
+		if r.pager.expired {
+			return nil, errors.NewResourceExpired("continue token expired")
+		}
+		offset, ok := strings.CutPrefix(opts.Continue, "page-")
+		n, err := strconv.Atoi(offset)
+		if !ok || err != nil {
+			return nil, errors.NewBadRequest("continue key is not valid")
+		}
+		start = n
+	}
+
+	list, err := r.ResourceInterface.List(ctx, v1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(list.Items, func(i, j int) bool { return list.Items[i].GetName() < list.Items[j].GetName() })
+
+	end := len(list.Items)
+	if opts.Limit > 0 && start+int(opts.Limit) < end {
+		end = start + int(opts.Limit)
+		list.SetContinue(pagingToken(end))
+	}
+	list.Items = list.Items[min(start, len(list.Items)):end]
+	return list, nil
+}
+
+// pagingToken returns the continue token pagingDynamicClient issues for the page starting at offset
+func pagingToken(offset int) string {
+	return fmt.Sprintf("page-%d", offset)
+}
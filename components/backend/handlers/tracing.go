@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the instrumentation scope of spans emitted by this package
+const tracerName = "ambient-code-backend/handlers"
+
+// Span names emitted by this package; dashboards and alerts key on these, so keep them stable
+const (
+	SpanValidateResourceAccess = "ambient.rbac.validate_access"
+	SpanParseRepoMap           = "ambient.repo.parse"
+	SpanRetry                  = "ambient.retry"
+)
+
+// Span attribute keys
+const (
+	AttrNamespace     = attribute.Key("k8s.namespace")
+	AttrRBACVerb      = attribute.Key("ambient.rbac.verb")
+	AttrRBACResource  = attribute.Key("ambient.rbac.resource")
+	AttrRBACName      = attribute.Key("ambient.rbac.name")
+	AttrRBACAllowed   = attribute.Key("ambient.rbac.allowed")
+	AttrRepoURL       = attribute.Key("ambient.repo.url")
+	AttrRepoErrorCode = attribute.Key("ambient.repo.error_code")
+	AttrRetryOp       = attribute.Key("ambient.retry.operation")
+	AttrRetryMax      = attribute.Key("ambient.retry.max_attempts")
+	AttrRetryAttempts = attribute.Key("ambient.retry.attempts")
+	AttrRetryOutcome  = attribute.Key("ambient.retry.outcome")
+)
+
+// TracerProvider supplies the tracer for spans in this package
+// nil uses the global OpenTelemetry provider, which is a no-op until one is installed
+var TracerProvider trace.TracerProvider
+
+// startSpan starts a span on the configured provider
+// With no provider installed the returned span records nothing
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	provider := TracerProvider
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+	return provider.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// hasParentSpan reports whether ctx carries a span for new spans to join
+func hasParentSpan(ctx context.Context) bool {
+	return trace.SpanContextFromContext(ctx).IsValid()
+}
+
+// endSpan records err on span (if any) and ends it
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// Reconnect pacing shared by every WatchWithBackoff caller
+// Variables rather than constants so tests can shorten them
+var (
+	// watchBackoff spaces out reconnects after consecutive short-lived watches
+	watchBackoff BackoffStrategy = ExponentialBackoff{Initial: time.Second, Max: 30 * time.Second}
+	// watchHealthyAfter is how long a watch must stay open to count as healthy and reset the backoff
+	watchHealthyAfter = time.Minute
+)
+
+// WatchWithBackoff runs startWatch and passes every event to handle, re-establishing the watch
+// whenever its result channel closes (the API server ends watches routinely) or startWatch fails
+// Reconnects wait according to watchBackoff; a watch that stayed open for watchHealthyAfter resets
+// the backoff, so a long-running watcher is not penalized for old failures
+// Error events are passed to handle like any other; the server closes the watch after sending one
+// Returns nil once ctx is cancelled, stopping the current watch. Errors that retrying cannot fix
+// (Forbidden, Unauthorized, Invalid) end the loop and are returned
+func WatchWithBackoff(ctx context.Context, startWatch func() (watch.Interface, error), handle func(watch.Event)) error {
+	attempt := 0
+	for {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		started := time.Now()
+		w, err := startWatch()
+		if err != nil {
+			switch ClassifyAPIError(err) {
+			case APIErrorForbidden, APIErrorUnauthorized, APIErrorInvalid:
+				return fmt.Errorf("failed to start watch: %w", err)
+			}
+			HelperLogger.Printf("%sWatch failed to start (attempt %d): %v", requestIDLogPrefix(ctx), attempt+1, err)
+		} else if !drainWatch(ctx, w, handle) {
+			return nil
+		} else if time.Since(started) >= watchHealthyAfter {
+			attempt = 0
+		}
+
+		delay := watchBackoff.Delay(attempt)
+		attempt++
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(delay):
+		}
+	}
+}
+
+// drainWatch delivers w's events to handle until the result channel closes (true) or ctx is
+// cancelled (false); w is stopped either way
+func drainWatch(ctx context.Context, w watch.Interface, handle func(watch.Event)) bool {
+	defer w.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case event, ok := <-w.ResultChan():
+			if !ok {
+				return true
+			}
+			handle(event)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+//go:build test
+
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	test_constants "ambient-code-backend/tests/constants"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+var _ = Describe("WatchWithBackoff", Label(test_constants.LabelUnit, test_constants.LabelHandlers), func() {
+	var backoff *recordingBackoff
+
+	BeforeEach(func() {
+		backoff = &recordingBackoff{}
+		previousBackoff, previousHealthy := watchBackoff, watchHealthyAfter
+		watchBackoff = backoff
+		DeferCleanup(func() { watchBackoff, watchHealthyAfter = previousBackoff, previousHealthy })
+	})
+
+	settings := func(name string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetName(name)
+		return obj
+	}
+
+	// closingThenOpen returns a startWatch whose first `closes` watches deliver one event and close,
+	// and whose next watch delivers one event and stays open
+	closingThenOpen := func(closes int, starts *int) func() (watch.Interface, error) {
+		return func() (watch.Interface, error) {
+			*starts++
+			fake := watch.NewFakeWithChanSize(1, false)
+			fake.Add(settings(fmt.Sprintf("event-%d", *starts)))
+			if *starts <= closes {
+				fake.Stop()
+			}
+			return fake, nil
+		}
+	}
+
+	It("Should reconnect after closures with growing backoff and keep the open watch", func() {
+		watchHealthyAfter = time.Hour
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		starts := 0
+		var names []string
+		done := make(chan error, 1)
+		go func() {
+			done <- WatchWithBackoff(ctx, closingThenOpen(3, &starts), func(e watch.Event) {
+				names = append(names, e.Object.(*unstructured.Unstructured).GetName())
+				if len(names) == 4 {
+					cancel()
+				}
+			})
+		}()
+
+		Eventually(done).Should(Receive(BeNil()))
+		Expect(starts).To(Equal(4))
+		Expect(names).To(Equal([]string{"event-1", "event-2", "event-3", "event-4"}))
+		Expect(backoff.attempts).To(Equal([]int{0, 1, 2}))
+	})
+
+	It("Should reset the backoff after a long-lived watch", func() {
+		watchHealthyAfter = 0
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		starts := 0
+		events := 0
+		done := make(chan error, 1)
+		go func() {
+			done <- WatchWithBackoff(ctx, closingThenOpen(3, &starts), func(watch.Event) {
+				events++
+				if events == 4 {
+					cancel()
+				}
+			})
+		}()
+
+		Eventually(done).Should(Receive(BeNil()))
+		Expect(backoff.attempts).To(Equal([]int{0, 0, 0}))
+	})
+
+	It("Should back off while the watch cannot be started", func() {
+		watchHealthyAfter = time.Hour
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		starts := 0
+		start := func() (watch.Interface, error) {
+			starts++
+			if starts <= 2 {
+				return nil, k8serrors.NewServiceUnavailable("apiserver restarting")
+			}
+			fake := watch.NewFakeWithChanSize(1, false)
+			fake.Add(settings("ready"))
+			return fake, nil
+		}
+		done := make(chan error, 1)
+		go func() {
+			done <- WatchWithBackoff(ctx, start, func(watch.Event) { cancel() })
+		}()
+
+		Eventually(done).Should(Receive(BeNil()))
+		Expect(starts).To(Equal(3))
+		Expect(backoff.attempts).To(Equal([]int{0, 1}))
+	})
+
+	It("Should stop the open watch when the context is cancelled", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		fake := watch.NewFake()
+		started := make(chan struct{})
+		done := make(chan error, 1)
+		go func() {
+			done <- WatchWithBackoff(ctx, func() (watch.Interface, error) {
+				close(started)
+				return fake, nil
+			}, func(watch.Event) {})
+		}()
+
+		Eventually(started).Should(BeClosed())
+		cancel()
+		Eventually(done).Should(Receive(BeNil()))
+		Expect(fake.IsStopped()).To(BeTrue())
+	})
+
+	It("Should return errors that retrying cannot fix", func() {
+		denied := k8serrors.NewForbidden(schema.GroupResource{Group: "vteam.ambient-code", Resource: "projectsettings"}, "", errors.New("no watch permission"))
+		starts := 0
+		err := WatchWithBackoff(context.Background(), func() (watch.Interface, error) {
+			starts++
+			return nil, denied
+		}, func(watch.Event) {})
+
+		Expect(errors.Is(err, denied)).To(BeTrue())
+		Expect(starts).To(Equal(1))
+		Expect(backoff.attempts).To(BeEmpty())
+	})
+
+	It("Should return immediately for an already-cancelled context", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		Expect(WatchWithBackoff(ctx, func() (watch.Interface, error) {
+			Fail("startWatch should not be called")
+			return nil, nil
+		}, func(watch.Event) {})).To(Succeed())
+	})
+})
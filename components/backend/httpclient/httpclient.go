@@ -0,0 +1,115 @@
+// Package httpclient builds the HTTP clients used for outbound Git and API calls.
+package httpclient
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// DefaultTimeout is used when neither Options.Timeout nor OUTBOUND_HTTP_TIMEOUT is set
+const DefaultTimeout = 15 * time.Second
+
+// Options configures a client built by New
+type Options struct {
+	// Timeout bounds each request (0 = OUTBOUND_HTTP_TIMEOUT, else DefaultTimeout)
+	Timeout time.Duration
+	// NoProxy lists extra hosts that bypass the proxy, in NO_PROXY syntax
+	// Combined with NO_PROXY and OUTBOUND_NO_PROXY from the environment
+	NoProxy []string
+}
+
+// NewClient is the factory used by outbound helpers
+// Replace it in tests to inject a stub transport for every caller at once
+var NewClient = New
+
+// sharedTransport pools connections for clients without extra NoProxy hosts
+var (
+	sharedTransportOnce sync.Once
+	sharedTransport     *http.Transport
+)
+
+// New returns a client that honors HTTP_PROXY, HTTPS_PROXY and NO_PROXY
+// A proxy set on the request context (see WithProxy, WithoutProxy) overrides the environment
+func New(opts Options) *http.Client {
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = timeoutFromEnv()
+	}
+
+	var transport *http.Transport
+	if len(opts.NoProxy) == 0 {
+		sharedTransportOnce.Do(func() {
+			sharedTransport = newTransport(nil)
+		})
+		transport = sharedTransport
+	} else {
+		transport = newTransport(opts.NoProxy)
+	}
+
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+func newTransport(extraNoProxy []string) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = proxyFunc(extraNoProxy)
+	return transport
+}
+
+func timeoutFromEnv() time.Duration {
+	raw := os.Getenv("OUTBOUND_HTTP_TIMEOUT")
+	if raw == "" {
+		return DefaultTimeout
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d <= 0 {
+		log.Printf("Invalid OUTBOUND_HTTP_TIMEOUT %q, using default %s", raw, DefaultTimeout)
+		return DefaultTimeout
+	}
+	return d
+}
+
+// proxyFunc resolves the proxy for a request from its context override or the environment
+// The environment is read per request so proxy changes apply without a restart
+func proxyFunc(extraNoProxy []string) func(*http.Request) (*url.URL, error) {
+	return func(req *http.Request) (*url.URL, error) {
+		if override, ok := req.Context().Value(proxyOverrideKey{}).(proxyOverride); ok {
+			return override.url, nil
+		}
+
+		cfg := httpproxy.FromEnvironment()
+		noProxy := []string{}
+		for _, list := range []string{cfg.NoProxy, os.Getenv("OUTBOUND_NO_PROXY")} {
+			if strings.TrimSpace(list) != "" {
+				noProxy = append(noProxy, list)
+			}
+		}
+		cfg.NoProxy = strings.Join(append(noProxy, extraNoProxy...), ",")
+		return cfg.ProxyFunc()(req.URL)
+	}
+}
+
+// proxyOverrideKey is the context key for a per-request proxy override
+type proxyOverrideKey struct{}
+
+// proxyOverride carries the proxy to use; a nil url means connect directly
+type proxyOverride struct {
+	url *url.URL
+}
+
+// WithProxy routes requests made with ctx through proxyURL regardless of the environment
+func WithProxy(ctx context.Context, proxyURL *url.URL) context.Context {
+	return context.WithValue(ctx, proxyOverrideKey{}, proxyOverride{url: proxyURL})
+}
+
+// WithoutProxy makes requests made with ctx connect directly regardless of the environment
+func WithoutProxy(ctx context.Context) context.Context {
+	return context.WithValue(ctx, proxyOverrideKey{}, proxyOverride{})
+}
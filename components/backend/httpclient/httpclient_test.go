@@ -0,0 +1,143 @@
+package httpclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// clearProxyEnv isolates a test from the proxy settings of the machine running it
+func clearProxyEnv(t *testing.T) {
+	t.Helper()
+	for _, key := range []string{"HTTP_PROXY", "http_proxy", "HTTPS_PROXY", "https_proxy", "NO_PROXY", "no_proxy", "OUTBOUND_NO_PROXY", "OUTBOUND_HTTP_TIMEOUT"} {
+		t.Setenv(key, "")
+	}
+}
+
+// newTestProxy starts a forward proxy stub that answers every request itself
+func newTestProxy(t *testing.T) (*httptest.Server, *url.URL, chan string) {
+	t.Helper()
+	seen := make(chan string, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Proxied requests carry the absolute target URL
+		seen <- r.URL.String()
+		_, _ = io.WriteString(w, "via proxy")
+	}))
+	t.Cleanup(srv.Close)
+	proxyURL, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatalf("failed to parse proxy URL: %v", err)
+	}
+	return srv, proxyURL, seen
+}
+
+func resolveProxy(t *testing.T, client *http.Client, req *http.Request) *url.URL {
+	t.Helper()
+	proxyURL, err := client.Transport.(*http.Transport).Proxy(req)
+	if err != nil {
+		t.Fatalf("Proxy() error: %v", err)
+	}
+	return proxyURL
+}
+
+func TestNewHonorsProxyEnv(t *testing.T) {
+	clearProxyEnv(t)
+	_, proxyURL, seen := newTestProxy(t)
+	t.Setenv("HTTP_PROXY", proxyURL.String())
+
+	resp, err := New(Options{}).Get("http://upstream.example/owner/repo")
+	if err != nil {
+		t.Fatalf("Get() error: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	if string(body) != "via proxy" {
+		t.Errorf("body = %q, want the proxy's response", body)
+	}
+	if got := <-seen; got != "http://upstream.example/owner/repo" {
+		t.Errorf("proxy saw %q, want the absolute target URL", got)
+	}
+}
+
+func TestNewHonorsNoProxy(t *testing.T) {
+	tests := []struct {
+		name  string
+		env   map[string]string
+		extra []string
+	}{
+		{name: "NO_PROXY", env: map[string]string{"NO_PROXY": "upstream.example"}},
+		{name: "OUTBOUND_NO_PROXY", env: map[string]string{"OUTBOUND_NO_PROXY": ".example"}},
+		{name: "Options.NoProxy", extra: []string{"upstream.example"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearProxyEnv(t)
+			t.Setenv("HTTPS_PROXY", "http://proxy.internal:3128")
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			client := New(Options{NoProxy: tt.extra})
+			direct := httptest.NewRequest(http.MethodGet, "https://upstream.example/", nil)
+			if got := resolveProxy(t, client, direct); got != nil {
+				t.Errorf("Proxy() = %v, want direct connection", got)
+			}
+			proxied := httptest.NewRequest(http.MethodGet, "https://api.github.com/", nil)
+			if got := resolveProxy(t, client, proxied); got == nil || got.Host != "proxy.internal:3128" {
+				t.Errorf("Proxy() = %v, want proxy.internal:3128", got)
+			}
+		})
+	}
+}
+
+func TestPerRequestProxyOverride(t *testing.T) {
+	clearProxyEnv(t)
+	t.Setenv("HTTP_PROXY", "http://proxy.internal:3128")
+	_, proxyURL, seen := newTestProxy(t)
+	client := New(Options{})
+
+	req, err := http.NewRequestWithContext(WithProxy(context.Background(), proxyURL), http.MethodGet, "http://upstream.example/", nil)
+	if err != nil {
+		t.Fatalf("NewRequest() error: %v", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do() error: %v", err)
+	}
+	resp.Body.Close()
+	if got := <-seen; got != "http://upstream.example/" {
+		t.Errorf("override proxy saw %q", got)
+	}
+
+	direct := httptest.NewRequest(http.MethodGet, "http://upstream.example/", nil).WithContext(WithoutProxy(context.Background()))
+	if got := resolveProxy(t, client, direct); got != nil {
+		t.Errorf("Proxy() = %v, want direct connection with WithoutProxy", got)
+	}
+}
+
+func TestNewTimeout(t *testing.T) {
+	clearProxyEnv(t)
+
+	if got := New(Options{}).Timeout; got != DefaultTimeout {
+		t.Errorf("default Timeout = %v, want %v", got, DefaultTimeout)
+	}
+
+	t.Setenv("OUTBOUND_HTTP_TIMEOUT", "3s")
+	if got := New(Options{}).Timeout; got != 3*time.Second {
+		t.Errorf("Timeout from env = %v, want 3s", got)
+	}
+	if got := New(Options{Timeout: time.Minute}).Timeout; got != time.Minute {
+		t.Errorf("explicit Timeout = %v, want 1m", got)
+	}
+
+	t.Setenv("OUTBOUND_HTTP_TIMEOUT", "soon")
+	if got := New(Options{}).Timeout; got != DefaultTimeout {
+		t.Errorf("invalid env Timeout = %v, want default", got)
+	}
+}
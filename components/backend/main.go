@@ -10,9 +10,12 @@ import (
 	"ambient-code-backend/handlers"
 	"ambient-code-backend/k8s"
 	"ambient-code-backend/server"
+	"ambient-code-backend/types"
 	"ambient-code-backend/websocket"
 
 	"github.com/joho/godotenv"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 // Build-time metadata (set via -ldflags -X during build)
@@ -52,6 +55,9 @@ func main() {
 	// Log build information
 	logBuildInfo()
 
+	// Self-hosted Git servers whose names don't reveal the provider (e.g. GitHub Enterprise Server)
+	types.SetProviderHosts(types.ParseProviderHosts(os.Getenv("GITHUB_ENTERPRISE_HOSTS"), os.Getenv("GITLAB_HOSTS")))
+
 	// Content service mode - minimal initialization, no K8s access needed
 	if os.Getenv("CONTENT_SERVICE_MODE") == "true" {
 		log.Println("Starting in CONTENT_SERVICE_MODE (no K8s client initialization)")
@@ -139,6 +145,27 @@ func main() {
 	handlers.BaseKubeConfig = server.BaseKubeConfig
 	handlers.K8sClientMw = server.K8sClient
 
+	// Check that the ProjectSettings CRD is installed; /ready reports it until it appears
+	handlers.ProjectSettingsCRDCheck = handlers.NewCRDChecker(server.K8sClient.Discovery(), k8s.GetProjectSettingsResource())
+	if err := handlers.ProjectSettingsCRDCheck.Refresh(); err != nil {
+		log.Printf("ERROR: %v; project settings handlers will fail until the CRD is applied (see components/manifests/base/crds)", err)
+	}
+	go handlers.ProjectSettingsCRDCheck.Run(context.Background(), handlers.DefaultCRDCheckInterval)
+
+	// Count sessions by phase for /metrics and /api/metrics/sessions
+	sessionMetrics, err := handlers.NewSessionPhaseCounter(server.DynamicClient, prometheus.DefaultRegisterer)
+	if err != nil {
+		log.Fatalf("Failed to set up session metrics: %v", err)
+	}
+	if err := sessionMetrics.Refresh(context.Background()); err != nil {
+		log.Printf("WARNING: %v; session metrics are unavailable until a refresh succeeds", err)
+	}
+	handlers.SessionMetrics = sessionMetrics
+	go sessionMetrics.Run(context.Background(), handlers.DefaultSessionMetricsInterval)
+
+	// Prometheus scrape endpoint on its own port, off the public API router
+	server.RunMetrics(promhttp.Handler())
+
 	// Initialize websocket package
 	websocket.StateBaseDir = server.StateBaseDir
 
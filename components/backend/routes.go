@@ -22,11 +22,15 @@ func registerContentRoutes(r *gin.Engine) {
 }
 
 func registerRoutes(r *gin.Engine) {
+	r.Use(handlers.RequestIDMiddleware())
+	r.Use(handlers.ContentNegotiationMiddleware())
+
 	// API routes
 	api := r.Group("/api")
 	{
 		// Public endpoints (no auth required)
 		api.GET("/workflows/ootb", handlers.ListOOTBWorkflows)
+		api.GET("/schema/repos", handlers.GetRepoConfigSchema)
 
 		api.POST("/projects/:projectName/agentic-sessions/:sessionName/github/token", handlers.MintSessionGitHubToken)
 
@@ -52,6 +56,8 @@ func registerRoutes(r *gin.Engine) {
 			projectGroup.POST("/agentic-sessions/:sessionName/clone", handlers.CloneSession)
 			projectGroup.POST("/agentic-sessions/:sessionName/start", handlers.StartSession)
 			projectGroup.POST("/agentic-sessions/:sessionName/stop", handlers.StopSession)
+			projectGroup.POST("/agentic-sessions/:sessionName/cancel", handlers.CancelSession)
+			projectGroup.GET("/agentic-sessions/:sessionName/permissions", handlers.GetSessionPermissions)
 			projectGroup.GET("/agentic-sessions/:sessionName/workspace", handlers.ListSessionWorkspace)
 			projectGroup.GET("/agentic-sessions/:sessionName/workspace/*path", handlers.GetSessionWorkspaceFile)
 			projectGroup.PUT("/agentic-sessions/:sessionName/workspace/*path", handlers.PutSessionWorkspaceFile)
@@ -62,8 +68,10 @@ func registerRoutes(r *gin.Engine) {
 			// Removed: git/pull, git/push, git/synchronize, git/create-branch, git/list-branches - agent handles all git operations
 			projectGroup.GET("/agentic-sessions/:sessionName/git/list-branches", handlers.GitListBranchesSession)
 			projectGroup.GET("/agentic-sessions/:sessionName/k8s-resources", handlers.GetSessionK8sResources)
+			projectGroup.GET("/agentic-sessions/:sessionName/logs", handlers.StreamSessionLogs)
 			projectGroup.POST("/agentic-sessions/:sessionName/workflow", handlers.SelectWorkflow)
 			projectGroup.GET("/agentic-sessions/:sessionName/workflow/metadata", handlers.GetWorkflowMetadata)
+			projectGroup.GET("/agentic-sessions/:sessionName/repos", handlers.GetSessionRepos)
 			projectGroup.POST("/agentic-sessions/:sessionName/repos", handlers.AddRepo)
 			// NOTE: /repos/status must come BEFORE /repos/:repoName to avoid wildcard matching
 			projectGroup.GET("/agentic-sessions/:sessionName/repos/status", handlers.GetReposStatus)
@@ -121,6 +129,10 @@ func registerRoutes(r *gin.Engine) {
 		// Cluster info endpoint (public, no auth required)
 		api.GET("/cluster-info", handlers.GetClusterInfo)
 
+		// Cluster-wide session counts by phase, from the background SessionMetrics refresh
+		// Requires cluster-wide list access to agenticsessions
+		api.GET("/metrics/sessions", handlers.GetSessionMetrics)
+
 		api.GET("/projects", handlers.ListProjects)
 		api.POST("/projects", handlers.CreateProject)
 		api.GET("/projects/:projectName", handlers.GetProject)
@@ -131,6 +143,9 @@ func registerRoutes(r *gin.Engine) {
 	// Health check endpoint
 	r.GET("/health", handlers.Health)
 
+	// Readiness endpoint: fails while required CRDs are missing
+	r.GET("/ready", handlers.Ready)
+
 	// Generic OAuth2 callback endpoint (outside /api for MCP compatibility)
 	r.GET("/oauth2callback", handlers.HandleOAuth2Callback)
 
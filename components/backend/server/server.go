@@ -45,7 +45,8 @@ func Run(registerRoutes RouterFunc) error {
 	config := cors.DefaultConfig()
 	config.AllowAllOrigins = true
 	config.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"}
-	config.AllowHeaders = []string{"Origin", "Content-Length", "Content-Type", "Authorization"}
+	config.AllowHeaders = []string{"Origin", "Content-Length", "Content-Type", "Authorization", "X-Request-ID"}
+	config.ExposeHeaders = []string{"X-Request-ID"}
 	r.Use(cors.New(config))
 
 	// Register routes
@@ -67,6 +68,30 @@ func Run(registerRoutes RouterFunc) error {
 	return nil
 }
 
+// RunMetrics serves handler at /metrics on METRICS_PORT (default 9090) in the background
+// The scrape endpoint is unauthenticated, so it stays off the API router and its port
+func RunMetrics(handler http.Handler) {
+	port := os.Getenv("METRICS_PORT")
+	if port == "" {
+		port = "9090"
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", handler)
+	srv := &http.Server{
+		Addr:              ":" + port,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	log.Printf("Metrics server starting on port %s", port)
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Printf("Metrics server error: %v", err)
+		}
+	}()
+}
+
 // forwardedIdentityMiddleware populates Gin context from common OAuth proxy headers
 func forwardedIdentityMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
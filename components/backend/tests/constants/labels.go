@@ -25,6 +25,7 @@ const (
 	LabelContent     = "content"
 	LabelDisplayName = "display-name"
 	LabelHealth      = "health"
+	LabelRepoConfig  = "repo-config"
 
 	// Specific component labels for other areas
 	LabelOperations = "operations" // for git operations
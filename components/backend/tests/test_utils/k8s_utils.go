@@ -6,6 +6,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -579,6 +580,10 @@ func (t *TypeSafeResourceInterface) ApplyStatus(ctx context.Context, name string
 func (t *TypeSafeResourceInterface) Create(ctx context.Context, obj *unstructured.Unstructured, options metav1.CreateOptions, subresources ...string) (*unstructured.Unstructured, error) {
 	// Convert the object to ensure DeepCopy compatibility
 	convertedObj := convertTypesForDeepCopy(obj)
+	// The fake tracker ignores DryRun; mimic the API server by returning the object unpersisted
+	if slices.Contains(options.DryRun, metav1.DryRunAll) {
+		return convertedObj.DeepCopy(), nil
+	}
 	return t.base.Create(ctx, convertedObj, options, subresources...)
 }
 
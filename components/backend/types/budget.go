@@ -0,0 +1,94 @@
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+// Budget caps what a single session may request from a tenant
+// A zero field means that dimension is unlimited, so new limits can be added without
+// changing the meaning of existing budgets
+type Budget struct {
+	// MaxRepos caps the number of repositories
+	MaxRepos int
+	// MaxCloneTime caps the summed clone timeouts of all repos; repos without a cloneTimeout
+	// count as DefaultCloneTimeout
+	MaxCloneTime        time.Duration
+	DefaultCloneTimeout time.Duration
+	// MaxEnvVars caps env entries plus environmentVariables entries
+	MaxEnvVars int
+	// MaxEnvBytes caps the summed length of env names and literal values (secret values are not known here)
+	MaxEnvBytes int
+	// MaxPromptBytes caps the length of the initial prompt
+	MaxPromptBytes int
+	// MaxTimeout caps the session's runner timeout
+	MaxTimeout time.Duration
+	// MaxTokens caps llmSettings.maxTokens
+	MaxTokens int
+}
+
+// BudgetExceededError names the budget limit a session went over
+type BudgetExceededError struct {
+	// Limit is the Budget field that was exceeded, e.g. "MaxRepos"
+	Limit   string
+	Max     string
+	Request string
+}
+
+func (e *BudgetExceededError) Error() string {
+	return fmt.Sprintf("session exceeds budget: %s is %s, over the limit of %s", e.Limit, e.Request, e.Max)
+}
+
+// ValidateSessionBudget checks the session's requested resources against budget
+// Returns a *BudgetExceededError for the first limit exceeded, checking fields in Budget order
+// Requests exactly at a limit are allowed. Pure computation: no API calls are made
+func ValidateSessionBudget(s AgenticSessionSpec, budget Budget) error {
+	if budget.MaxRepos > 0 && len(s.Repos) > budget.MaxRepos {
+		return exceeded("MaxRepos", budget.MaxRepos, len(s.Repos))
+	}
+	if budget.MaxCloneTime > 0 {
+		var total time.Duration
+		for _, r := range s.Repos {
+			total += r.CloneTimeoutOr(budget.DefaultCloneTimeout)
+		}
+		if total > budget.MaxCloneTime {
+			return exceeded("MaxCloneTime", budget.MaxCloneTime, total)
+		}
+	}
+
+	envVars := len(s.Env) + len(s.EnvironmentVariables)
+	if budget.MaxEnvVars > 0 && envVars > budget.MaxEnvVars {
+		return exceeded("MaxEnvVars", budget.MaxEnvVars, envVars)
+	}
+	if budget.MaxEnvBytes > 0 {
+		size := 0
+		for _, e := range s.Env {
+			size += len(e.Name)
+			if e.Value != nil {
+				size += len(*e.Value)
+			}
+		}
+		for name, value := range s.EnvironmentVariables {
+			size += len(name) + len(value)
+		}
+		if size > budget.MaxEnvBytes {
+			return exceeded("MaxEnvBytes", budget.MaxEnvBytes, size)
+		}
+	}
+
+	if budget.MaxPromptBytes > 0 && len(s.InitialPrompt) > budget.MaxPromptBytes {
+		return exceeded("MaxPromptBytes", budget.MaxPromptBytes, len(s.InitialPrompt))
+	}
+	timeout := time.Duration(s.Timeout) * time.Second
+	if budget.MaxTimeout > 0 && timeout > budget.MaxTimeout {
+		return exceeded("MaxTimeout", budget.MaxTimeout, timeout)
+	}
+	if budget.MaxTokens > 0 && s.LLMSettings.MaxTokens > budget.MaxTokens {
+		return exceeded("MaxTokens", budget.MaxTokens, s.LLMSettings.MaxTokens)
+	}
+	return nil
+}
+
+func exceeded(limit string, maxValue, request interface{}) *BudgetExceededError {
+	return &BudgetExceededError{Limit: limit, Max: fmt.Sprint(maxValue), Request: fmt.Sprint(request)}
+}
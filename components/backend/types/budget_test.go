@@ -0,0 +1,105 @@
+package types
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateSessionBudget(t *testing.T) {
+	repos := func(n int) []SimpleRepo {
+		out := make([]SimpleRepo, n)
+		for i := range out {
+			out[i] = SimpleRepo{URL: "https://github.com/owner/repo.git"}
+		}
+		return out
+	}
+	envOfSize := func(valueLen int) []EnvVar {
+		return []EnvVar{{Name: "ABC", Value: StringPtr(strings.Repeat("x", valueLen))}}
+	}
+
+	tests := []struct {
+		name      string
+		spec      AgenticSessionSpec
+		budget    Budget
+		wantLimit string
+	}{
+		{name: "zero budget is unlimited", spec: AgenticSessionSpec{Repos: repos(50), Timeout: 86400}, budget: Budget{}},
+		{name: "repos at limit", spec: AgenticSessionSpec{Repos: repos(3)}, budget: Budget{MaxRepos: 3}},
+		{name: "repos over limit", spec: AgenticSessionSpec{Repos: repos(4)}, budget: Budget{MaxRepos: 3}, wantLimit: "MaxRepos"},
+		{
+			name:   "clone time at limit",
+			spec:   AgenticSessionSpec{Repos: append(repos(1), SimpleRepo{URL: "https://github.com/owner/big.git", CloneTimeout: &Duration{Duration: 10 * time.Minute}})},
+			budget: Budget{MaxCloneTime: 15 * time.Minute, DefaultCloneTimeout: 5 * time.Minute},
+		},
+		{
+			name:      "clone time over limit",
+			spec:      AgenticSessionSpec{Repos: append(repos(2), SimpleRepo{URL: "https://github.com/owner/big.git", CloneTimeout: &Duration{Duration: 10 * time.Minute}})},
+			budget:    Budget{MaxCloneTime: 15 * time.Minute, DefaultCloneTimeout: 5 * time.Minute},
+			wantLimit: "MaxCloneTime",
+		},
+		{
+			name:   "env vars at limit",
+			spec:   AgenticSessionSpec{Env: envOfSize(1), EnvironmentVariables: map[string]string{"B": "1"}},
+			budget: Budget{MaxEnvVars: 2},
+		},
+		{
+			name:      "env vars over limit",
+			spec:      AgenticSessionSpec{Env: envOfSize(1), EnvironmentVariables: map[string]string{"B": "1", "C": "2"}},
+			budget:    Budget{MaxEnvVars: 2},
+			wantLimit: "MaxEnvVars",
+		},
+		{name: "env bytes at limit", spec: AgenticSessionSpec{Env: envOfSize(7)}, budget: Budget{MaxEnvBytes: 10}},
+		{name: "env bytes over limit", spec: AgenticSessionSpec{Env: envOfSize(8)}, budget: Budget{MaxEnvBytes: 10}, wantLimit: "MaxEnvBytes"},
+		{
+			name:   "secret values do not count toward env bytes",
+			spec:   AgenticSessionSpec{Env: []EnvVar{{Name: "TOKEN", ValueFromSecret: &SecretRef{Name: "creds", Key: "a-very-long-key-name"}}}},
+			budget: Budget{MaxEnvBytes: 5},
+		},
+		{
+			name:      "environmentVariables count toward env bytes",
+			spec:      AgenticSessionSpec{EnvironmentVariables: map[string]string{"ABC": "12345678"}},
+			budget:    Budget{MaxEnvBytes: 10},
+			wantLimit: "MaxEnvBytes",
+		},
+		{name: "prompt at limit", spec: AgenticSessionSpec{InitialPrompt: "fix it"}, budget: Budget{MaxPromptBytes: 6}},
+		{name: "prompt over limit", spec: AgenticSessionSpec{InitialPrompt: "fix it!"}, budget: Budget{MaxPromptBytes: 6}, wantLimit: "MaxPromptBytes"},
+		{name: "timeout at limit", spec: AgenticSessionSpec{Timeout: 3600}, budget: Budget{MaxTimeout: time.Hour}},
+		{name: "timeout over limit", spec: AgenticSessionSpec{Timeout: 3601}, budget: Budget{MaxTimeout: time.Hour}, wantLimit: "MaxTimeout"},
+		{name: "tokens at limit", spec: AgenticSessionSpec{LLMSettings: LLMSettings{MaxTokens: 8000}}, budget: Budget{MaxTokens: 8000}},
+		{name: "tokens over limit", spec: AgenticSessionSpec{LLMSettings: LLMSettings{MaxTokens: 8001}}, budget: Budget{MaxTokens: 8000}, wantLimit: "MaxTokens"},
+		{
+			name:      "first exceeded limit is reported",
+			spec:      AgenticSessionSpec{Repos: repos(2), InitialPrompt: "too long"},
+			budget:    Budget{MaxRepos: 1, MaxPromptBytes: 1},
+			wantLimit: "MaxRepos",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSessionBudget(tt.spec, tt.budget)
+			if tt.wantLimit == "" {
+				if err != nil {
+					t.Fatalf("ValidateSessionBudget() unexpected error: %v", err)
+				}
+				return
+			}
+			var exceeded *BudgetExceededError
+			if !errors.As(err, &exceeded) {
+				t.Fatalf("ValidateSessionBudget() error = %v, want *BudgetExceededError", err)
+			}
+			if exceeded.Limit != tt.wantLimit {
+				t.Errorf("ValidateSessionBudget() limit = %q, want %q", exceeded.Limit, tt.wantLimit)
+			}
+		})
+	}
+}
+
+func TestBudgetExceededErrorMessage(t *testing.T) {
+	err := ValidateSessionBudget(AgenticSessionSpec{Timeout: 7200}, Budget{MaxTimeout: time.Hour})
+	want := "session exceeds budget: MaxTimeout is 2h0m0s, over the limit of 1h0m0s"
+	if err == nil || err.Error() != want {
+		t.Errorf("ValidateSessionBudget() error = %v, want %q", err, want)
+	}
+}
@@ -0,0 +1,88 @@
+package types
+
+import "slices"
+
+// Clone filters accepted by SimpleRepo.CloneFilter
+// The clone step maps treeless to git's --filter=tree:0 and blobless to --filter=blob:none;
+// both fetch the omitted objects on demand, so they suit large repos the agent only partly reads
+const (
+	CloneFilterFull     = "full"
+	CloneFilterTreeless = "treeless"
+	CloneFilterBlobless = "blobless"
+)
+
+// CloneFilters lists the valid clone filters
+var CloneFilters = []string{CloneFilterFull, CloneFilterTreeless, CloneFilterBlobless}
+
+// IsValidCloneFilter reports whether filter is one of CloneFilters
+func IsValidCloneFilter(filter string) bool {
+	return slices.Contains(CloneFilters, filter)
+}
+
+// CloneFilterOrDefault returns the repo's clone filter, or CloneFilterFull when none is set
+func (r SimpleRepo) CloneFilterOrDefault() string {
+	if r.CloneFilter != nil {
+		return *r.CloneFilter
+	}
+	return CloneFilterFull
+}
+
+// EffectiveDepth returns the shallow clone depth to use for repo, or nil for a full clone
+// The repo's own depth wins over the project's default. A depth up to settings.RepoLimits.MaxDepth
+// is used as is; a larger one is clamped to MaxDepth, or becomes a full clone when it is also
+// above FullCloneDepth. Depths below 1 are ignored
+func EffectiveDepth(repo SimpleRepo, settings ProjectSettings) *int {
+	depth := repo.Depth
+	if depth == nil && settings.RepoDefaults != nil {
+		depth = settings.RepoDefaults.Depth
+	}
+	if depth == nil || *depth < 1 {
+		return nil
+	}
+
+	var limits RepoLimits
+	if settings.RepoLimits != nil {
+		limits = *settings.RepoLimits
+	}
+	if limits.MaxDepth > 0 && *depth <= limits.MaxDepth {
+		return IntPtr(*depth)
+	}
+	if limits.FullCloneDepth > 0 && *depth > limits.FullCloneDepth {
+		return nil
+	}
+	if limits.MaxDepth > 0 {
+		return IntPtr(limits.MaxDepth)
+	}
+	return IntPtr(*depth)
+}
+
+// DefaultCloneConcurrency is how many full clones may run at once before they start
+// competing for network bandwidth
+const DefaultCloneConcurrency = 4
+
+// RecommendCloneConcurrency returns how many of repos to clone in parallel
+// Repos with a BaseCommit only fetch the range since it, so they cost half a full clone and
+// a mostly incremental set gets up to twice DefaultCloneConcurrency workers
+// The result never exceeds len(repos) or maxConcurrency (<= 0 = no caller limit) and is at
+// least 1 for a non-empty list; priorities only order the clones, so they do not affect it
+func RecommendCloneConcurrency(repos []SimpleRepo, maxConcurrency int) int {
+	if len(repos) == 0 {
+		return 0
+	}
+	// Cost in half-clone units: 2 for a full clone, 1 for an incremental fetch
+	cost := 0
+	for _, r := range repos {
+		if nonBlank(r.InputLocation().BaseCommit) != "" {
+			cost++
+		} else {
+			cost += 2
+		}
+	}
+	// As many workers as fit the bandwidth budget at the set's average cost
+	workers := 2 * DefaultCloneConcurrency * len(repos) / cost
+	workers = min(workers, len(repos))
+	if maxConcurrency > 0 {
+		workers = min(workers, maxConcurrency)
+	}
+	return max(workers, 1)
+}
@@ -0,0 +1,119 @@
+package types
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestRecommendCloneConcurrency(t *testing.T) {
+	repos := func(full, incremental int) []SimpleRepo {
+		var out []SimpleRepo
+		for range full {
+			out = append(out, SimpleRepo{Input: &RepoLocation{URL: "https://github.com/owner/repo.git"}})
+		}
+		for range incremental {
+			out = append(out, SimpleRepo{Input: &RepoLocation{URL: "https://github.com/owner/repo.git", BaseCommit: StringPtr("0123456789abcdef0123456789abcdef01234567")}})
+		}
+		return out
+	}
+
+	tests := []struct {
+		name           string
+		repos          []SimpleRepo
+		maxConcurrency int
+		want           int
+	}{
+		{name: "no repos", repos: nil, maxConcurrency: 8, want: 0},
+		{name: "single repo", repos: repos(1, 0), want: 1},
+		{name: "fewer repos than the default", repos: repos(3, 0), want: 3},
+		{name: "many full clones", repos: repos(10, 0), want: DefaultCloneConcurrency},
+		{name: "many full clones under a low cap", repos: repos(10, 0), maxConcurrency: 2, want: 2},
+		{name: "high cap does not raise full clones", repos: repos(10, 0), maxConcurrency: 16, want: DefaultCloneConcurrency},
+		{name: "negative cap is no limit", repos: repos(3, 0), maxConcurrency: -1, want: 3},
+		{name: "cap of one", repos: repos(0, 10), maxConcurrency: 1, want: 1},
+		{name: "incremental fetches double the default", repos: repos(0, 10), want: 2 * DefaultCloneConcurrency},
+		{name: "incremental fetches under a cap", repos: repos(0, 10), maxConcurrency: 6, want: 6},
+		{name: "large incremental set", repos: repos(0, 100), maxConcurrency: 32, want: 2 * DefaultCloneConcurrency},
+		{name: "mixed set", repos: repos(5, 5), want: 5},
+		{name: "large mixed set", repos: repos(90, 10), maxConcurrency: 50, want: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RecommendCloneConcurrency(tt.repos, tt.maxConcurrency); got != tt.want {
+				t.Errorf("RecommendCloneConcurrency() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecommendCloneConcurrencyBlankBaseCommit(t *testing.T) {
+	repos := make([]SimpleRepo, 10)
+	for i := range repos {
+		repos[i] = SimpleRepo{Input: &RepoLocation{URL: "https://github.com/owner/repo.git", BaseCommit: StringPtr(" ")}}
+	}
+	if got := RecommendCloneConcurrency(repos, 0); got != DefaultCloneConcurrency {
+		t.Errorf("RecommendCloneConcurrency() = %d, want %d (blank baseCommit is a full clone)", got, DefaultCloneConcurrency)
+	}
+}
+
+func TestEffectiveDepth(t *testing.T) {
+	limits := func(maxDepth, fullCloneDepth int) ProjectSettings {
+		return ProjectSettings{RepoLimits: &RepoLimits{MaxDepth: maxDepth, FullCloneDepth: fullCloneDepth}}
+	}
+	withDefault := func(settings ProjectSettings, depth int) ProjectSettings {
+		settings.RepoDefaults = &RepoDefaults{Depth: IntPtr(depth)}
+		return settings
+	}
+	repo := func(depth *int) SimpleRepo {
+		return SimpleRepo{URL: "https://github.com/owner/repo.git", Depth: depth}
+	}
+
+	tests := []struct {
+		name     string
+		repo     SimpleRepo
+		settings ProjectSettings
+		want     *int
+	}{
+		{name: "no depth is a full clone", repo: repo(nil), settings: limits(100, 1000), want: nil},
+		{name: "no limits pass through", repo: repo(IntPtr(5000)), want: IntPtr(5000)},
+		{name: "explicit depth under the max", repo: repo(IntPtr(50)), settings: limits(100, 1000), want: IntPtr(50)},
+		{name: "explicit depth at the max", repo: repo(IntPtr(100)), settings: limits(100, 1000), want: IntPtr(100)},
+		{name: "explicit depth clamped to the max", repo: repo(IntPtr(500)), settings: limits(100, 1000), want: IntPtr(100)},
+		{name: "depth at the threshold is clamped", repo: repo(IntPtr(1000)), settings: limits(100, 1000), want: IntPtr(100)},
+		{name: "depth over the threshold is a full clone", repo: repo(IntPtr(1001)), settings: limits(100, 1000), want: nil},
+		{name: "threshold without a max", repo: repo(IntPtr(5000)), settings: limits(0, 1000), want: nil},
+		{name: "under the threshold without a max", repo: repo(IntPtr(10)), settings: limits(0, 1000), want: IntPtr(10)},
+		{name: "max without a threshold", repo: repo(IntPtr(1 << 20)), settings: limits(100, 0), want: IntPtr(100)},
+		{name: "explicit depth under the max ignores a lower threshold", repo: repo(IntPtr(50)), settings: limits(100, 10), want: IntPtr(50)},
+		{name: "project default", repo: repo(nil), settings: withDefault(limits(100, 1000), 20), want: IntPtr(20)},
+		{name: "huge project default becomes a full clone", repo: repo(nil), settings: withDefault(limits(100, 1000), 1000000), want: nil},
+		{name: "explicit depth wins over the default", repo: repo(IntPtr(5)), settings: withDefault(limits(100, 1000), 1000000), want: IntPtr(5)},
+		{name: "non-positive depth is ignored", repo: repo(IntPtr(0)), settings: limits(100, 1000), want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EffectiveDepth(tt.repo, tt.settings)
+			switch {
+			case got == nil && tt.want == nil:
+			case got == nil || tt.want == nil || *got != *tt.want:
+				t.Errorf("EffectiveDepth() = %s, want %s", formatDepth(got), formatDepth(tt.want))
+			}
+		})
+	}
+}
+
+func TestEffectiveDepthDoesNotAliasInput(t *testing.T) {
+	depth := IntPtr(10)
+	got := EffectiveDepth(SimpleRepo{Depth: depth}, ProjectSettings{})
+	*got = 99
+	if *depth != 10 {
+		t.Errorf("EffectiveDepth() result aliases the repo's depth")
+	}
+}
+
+func formatDepth(depth *int) string {
+	if depth == nil {
+		return "nil (full clone)"
+	}
+	return fmt.Sprint(*depth)
+}
@@ -0,0 +1,34 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestDurationJSON(t *testing.T) {
+	var repo SimpleRepo
+	if err := json.Unmarshal([]byte(`{"url": "https://github.com/owner/repo.git", "cloneTimeout": "90s"}`), &repo); err != nil {
+		t.Fatalf("Unmarshal() error: %v", err)
+	}
+	if repo.CloneTimeout == nil || repo.CloneTimeout.Duration != 90*time.Second {
+		t.Fatalf("CloneTimeout = %v, want 90s", repo.CloneTimeout)
+	}
+
+	out, err := json.Marshal(repo.CloneTimeout)
+	if err != nil {
+		t.Fatalf("Marshal() error: %v", err)
+	}
+	if string(out) != `"1m30s"` {
+		t.Errorf("Marshal() = %s, want \"1m30s\"", out)
+	}
+}
+
+func TestDurationJSONRejectsInvalid(t *testing.T) {
+	for _, input := range []string{`"-1m"`, `"0s"`, `"later"`, `60`} {
+		var d Duration
+		if err := json.Unmarshal([]byte(input), &d); err == nil {
+			t.Errorf("Unmarshal(%s) = %v, want error", input, d)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package types
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Environment variable names follow the POSIX shell convention
+var envVarNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// SecretRef points at a key within a Secret in the session's namespace
+// Namespace is optional and, when set, must match the session namespace
+type SecretRef struct {
+	Name      string `json:"name"`
+	Key       string `json:"key"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// SplitSecretName splits a possibly namespace-qualified secret name ("ns/name")
+// A bare name returns an empty namespace
+func SplitSecretName(name string) (namespace, secret string) {
+	if i := strings.Index(name, "/"); i >= 0 {
+		return name[:i], name[i+1:]
+	}
+	return "", name
+}
+
+// ResolveSecretNamespace checks the reference stays within namespace and returns it in bare form
+// A qualified name or namespace field naming any other namespace is rejected
+func (r SecretRef) ResolveSecretNamespace(namespace string) (SecretRef, error) {
+	ns, name := SplitSecretName(r.Name)
+	if ns != "" && ns != namespace {
+		return r, fmt.Errorf("secret %q is in namespace %q; only secrets in namespace %q may be referenced", r.Name, ns, namespace)
+	}
+	if r.Namespace != "" && r.Namespace != namespace {
+		return r, fmt.Errorf("secret %q is in namespace %q; only secrets in namespace %q may be referenced", name, r.Namespace, namespace)
+	}
+	if strings.TrimSpace(name) == "" || strings.Contains(name, "/") {
+		return r, fmt.Errorf("invalid secret name %q", r.Name)
+	}
+	return SecretRef{Name: name, Key: r.Key}, nil
+}
+
+// ResolveEnvSecretRefs rewrites each secret reference in env to its bare form, rejecting cross-namespace references
+func ResolveEnvSecretRefs(env []EnvVar, namespace string) error {
+	for i, e := range env {
+		if e.ValueFromSecret == nil {
+			continue
+		}
+		ref, err := e.ValueFromSecret.ResolveSecretNamespace(namespace)
+		if err != nil {
+			return fmt.Errorf("env[%d] (%s): valueFromSecret: %w", i, e.Name, err)
+		}
+		env[i].ValueFromSecret = &ref
+	}
+	return nil
+}
+
+// EnvVar is an environment variable injected into the session runner
+// Exactly one of Value or ValueFromSecret must be set
+type EnvVar struct {
+	Name            string     `json:"name"`
+	Value           *string    `json:"value,omitempty"`
+	ValueFromSecret *SecretRef `json:"valueFromSecret,omitempty"`
+}
+
+// ValidateEnvVars checks names are valid and unique and that each entry sets exactly one value source
+func ValidateEnvVars(env []EnvVar) error {
+	seen := make(map[string]bool, len(env))
+	for i, e := range env {
+		if !envVarNameRegex.MatchString(e.Name) {
+			return fmt.Errorf("env[%d]: invalid name %q", i, e.Name)
+		}
+		if seen[e.Name] {
+			return fmt.Errorf("env[%d]: duplicate name %q", i, e.Name)
+		}
+		seen[e.Name] = true
+
+		hasValue := e.Value != nil
+		hasSecret := e.ValueFromSecret != nil
+		if hasValue == hasSecret {
+			return fmt.Errorf("env[%d] (%s): exactly one of value or valueFromSecret must be set", i, e.Name)
+		}
+		if hasSecret && (strings.TrimSpace(e.ValueFromSecret.Name) == "" || strings.TrimSpace(e.ValueFromSecret.Key) == "") {
+			return fmt.Errorf("env[%d] (%s): valueFromSecret requires name and key", i, e.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package types
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateEnvVars(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     []EnvVar
+		wantErr string
+	}{
+		{
+			name: "valid literal and secret values",
+			env: []EnvVar{
+				{Name: "FEATURE_X", Value: StringPtr("on")},
+				{Name: "_TOKEN", ValueFromSecret: &SecretRef{Name: "creds", Key: "token"}},
+				{Name: "EMPTY_OK", Value: StringPtr("")},
+			},
+		},
+		{
+			name:    "duplicate name",
+			env:     []EnvVar{{Name: "A", Value: StringPtr("1")}, {Name: "A", Value: StringPtr("2")}},
+			wantErr: "duplicate name",
+		},
+		{
+			name:    "invalid name characters",
+			env:     []EnvVar{{Name: "BAD-NAME", Value: StringPtr("1")}},
+			wantErr: "invalid name",
+		},
+		{
+			name:    "name starting with digit",
+			env:     []EnvVar{{Name: "1ABC", Value: StringPtr("1")}},
+			wantErr: "invalid name",
+		},
+		{
+			name:    "neither value nor secret",
+			env:     []EnvVar{{Name: "A"}},
+			wantErr: "exactly one of",
+		},
+		{
+			name:    "both value and secret",
+			env:     []EnvVar{{Name: "A", Value: StringPtr("1"), ValueFromSecret: &SecretRef{Name: "s", Key: "k"}}},
+			wantErr: "exactly one of",
+		},
+		{
+			name:    "secret ref missing key",
+			env:     []EnvVar{{Name: "A", ValueFromSecret: &SecretRef{Name: "s"}}},
+			wantErr: "requires name and key",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateEnvVars(tt.env)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("ValidateEnvVars() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateEnvVars() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResolveEnvSecretRefs(t *testing.T) {
+	tests := []struct {
+		name     string
+		ref      SecretRef
+		wantName string
+		wantErr  string
+	}{
+		{name: "bare name", ref: SecretRef{Name: "creds", Key: "token"}, wantName: "creds"},
+		{name: "same-namespace qualified name", ref: SecretRef{Name: "proj/creds", Key: "token"}, wantName: "creds"},
+		{name: "same-namespace field", ref: SecretRef{Name: "creds", Key: "token", Namespace: "proj"}, wantName: "creds"},
+		{name: "cross-namespace qualified name", ref: SecretRef{Name: "other-ns/creds", Key: "token"}, wantErr: `namespace "other-ns"`},
+		{name: "cross-namespace field", ref: SecretRef{Name: "creds", Key: "token", Namespace: "other-ns"}, wantErr: `namespace "other-ns"`},
+		{name: "qualified name with empty secret", ref: SecretRef{Name: "proj/", Key: "token"}, wantErr: "invalid secret name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref := tt.ref
+			env := []EnvVar{{Name: "TOKEN", ValueFromSecret: &ref}}
+			err := ResolveEnvSecretRefs(env, "proj")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ResolveEnvSecretRefs() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveEnvSecretRefs() unexpected error: %v", err)
+			}
+			got := env[0].ValueFromSecret
+			if got.Name != tt.wantName || got.Namespace != "" || got.Key != "token" {
+				t.Errorf("ResolveEnvSecretRefs() ref = %+v, want bare name %q", *got, tt.wantName)
+			}
+		})
+	}
+}
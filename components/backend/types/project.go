@@ -17,3 +17,29 @@ type CreateProjectRequest struct {
 	DisplayName string `json:"displayName,omitempty"` // Optional: only used on OpenShift
 	Description string `json:"description,omitempty"` // Optional: only used on OpenShift
 }
+
+// ProjectSettings is the spec of a project's ProjectSettings CR, limited to the fields the backend reads
+type ProjectSettings struct {
+	RepoDefaults *RepoDefaults `json:"repoDefaults,omitempty"`
+	RepoLimits   *RepoLimits   `json:"repoLimits,omitempty"`
+}
+
+// RepoDefaults are project-wide values for repo fields a session leaves unset
+// Each field mirrors the SimpleRepo field of the same name; nil means no default
+type RepoDefaults struct {
+	AutoPush          *bool     `json:"autoPush,omitempty"`
+	RecurseSubmodules *bool     `json:"recurseSubmodules,omitempty"`
+	CloneTimeout      *Duration `json:"cloneTimeout,omitempty"`
+	// CreateBranch applies only to repos that specify an output
+	CreateBranch *bool `json:"createBranch,omitempty"`
+	Depth        *int  `json:"depth,omitempty"`
+}
+
+// RepoLimits bound what session repos may request in a project; zero means no limit
+type RepoLimits struct {
+	// MaxDepth caps a shallow clone's depth
+	MaxDepth int `json:"maxDepth,omitempty"`
+	// FullCloneDepth turns depths above both it and MaxDepth into full clones, since fetching
+	// that much history shallowly costs the server more than a plain clone
+	FullCloneDepth int `json:"fullCloneDepth,omitempty"`
+}
@@ -3,6 +3,7 @@ package types
 import (
 	"net/url"
 	"strings"
+	"sync"
 )
 
 // ProviderType distinguishes between Git hosting providers
@@ -15,8 +16,81 @@ const (
 	ProviderGitLab ProviderType = "gitlab"
 )
 
+// providerHosts holds the configured host→provider mapping (see SetProviderHosts)
+var providerHosts = struct {
+	sync.RWMutex
+	hosts map[string]ProviderType
+}{}
+
+// SetProviderHosts replaces the configured host→provider mapping
+// Configured hosts are matched exactly (case-insensitive, no port) and take precedence over the
+// built-in name heuristics, e.g. to mark code.acme.com as a GitHub Enterprise Server host
+// A nil or empty map restores heuristics-only detection
+func SetProviderHosts(hosts map[string]ProviderType) {
+	normalized := make(map[string]ProviderType, len(hosts))
+	for host, provider := range hosts {
+		if host = strings.ToLower(strings.TrimSpace(host)); host != "" {
+			normalized[host] = provider
+		}
+	}
+	providerHosts.Lock()
+	defer providerHosts.Unlock()
+	providerHosts.hosts = normalized
+}
+
+// ParseProviderHosts builds a mapping for SetProviderHosts from comma-separated host lists,
+// e.g. the GITHUB_ENTERPRISE_HOSTS and GITLAB_HOSTS environment variables
+// A host listed for both providers maps to GitLab
+func ParseProviderHosts(githubHosts, gitlabHosts string) map[string]ProviderType {
+	hosts := make(map[string]ProviderType)
+	for _, list := range []struct {
+		hosts    string
+		provider ProviderType
+	}{{githubHosts, ProviderGitHub}, {gitlabHosts, ProviderGitLab}} {
+		for _, host := range strings.Split(list.hosts, ",") {
+			if host = strings.ToLower(strings.TrimSpace(host)); host != "" {
+				hosts[host] = list.provider
+			}
+		}
+	}
+	return hosts
+}
+
+// ProviderForHost classifies a hostname: configured hosts first, then the name heuristics
+// Returns "" for hosts that are neither GitHub nor GitLab
+func ProviderForHost(hostname string) ProviderType {
+	hostname = strings.ToLower(hostname)
+	if hostname == "" {
+		return ""
+	}
+
+	providerHosts.RLock()
+	provider, ok := providerHosts.hosts[hostname]
+	providerHosts.RUnlock()
+	if ok {
+		return provider
+	}
+
+	// Check for GitHub:
+	// - github.com (public)
+	// - *.github.com (enterprise variants like company.github.com)
+	// - github.* (enterprise variants like github.company.com)
+	if hostname == "github.com" || strings.HasSuffix(hostname, ".github.com") || strings.HasPrefix(hostname, "github.") {
+		return ProviderGitHub
+	}
+
+	// Check for GitLab (gitlab.com or any hostname containing "gitlab" for self-hosted)
+	// GitLab self-hosted instances typically use gitlab.company.com or gitlab-ce.company.com
+	if hostname == "gitlab.com" || strings.Contains(hostname, "gitlab") {
+		return ProviderGitLab
+	}
+
+	// Unknown provider
+	return ""
+}
+
 // DetectProvider determines the Git provider from a repository URL
-// Uses precise hostname matching to prevent false positives
+// Uses precise hostname matching to prevent false positives; see ProviderForHost
 func DetectProvider(repoURL string) ProviderType {
 	if repoURL == "" {
 		return ""
@@ -44,27 +118,7 @@ func DetectProvider(repoURL string) ProviderType {
 		return ""
 	}
 
-	hostname := strings.ToLower(parsedURL.Hostname())
-	if hostname == "" {
-		return ""
-	}
-
-	// Check for GitHub:
-	// - github.com (public)
-	// - *.github.com (enterprise variants like company.github.com)
-	// - github.* (enterprise variants like github.company.com)
-	if hostname == "github.com" || strings.HasSuffix(hostname, ".github.com") || strings.HasPrefix(hostname, "github.") {
-		return ProviderGitHub
-	}
-
-	// Check for GitLab (gitlab.com or any hostname containing "gitlab" for self-hosted)
-	// GitLab self-hosted instances typically use gitlab.company.com or gitlab-ce.company.com
-	if hostname == "gitlab.com" || strings.Contains(hostname, "gitlab") {
-		return ProviderGitLab
-	}
-
-	// Unknown provider
-	return ""
+	return ProviderForHost(parsedURL.Hostname())
 }
 
 // String returns the string representation of the provider type
@@ -0,0 +1,58 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDetectProviderConfiguredHosts(t *testing.T) {
+	SetProviderHosts(map[string]ProviderType{"Code.Acme.com": ProviderGitHub, "git.acme.com": ProviderGitLab, "github.acme.com": ProviderGitLab})
+	t.Cleanup(func() { SetProviderHosts(nil) })
+
+	tests := []struct {
+		name string
+		url  string
+		want ProviderType
+	}{
+		{name: "configured GitHub Enterprise host", url: "https://code.acme.com/team/repo.git", want: ProviderGitHub},
+		{name: "configured host over ssh", url: "git@code.acme.com:team/repo.git", want: ProviderGitHub},
+		{name: "configured GitLab host", url: "https://git.acme.com/group/repo.git", want: ProviderGitLab},
+		{name: "configuration overrides heuristics", url: "https://github.acme.com/group/repo.git", want: ProviderGitLab},
+		{name: "heuristic GitHub host", url: "https://github.com/owner/repo.git", want: ProviderGitHub},
+		{name: "heuristic GitLab host", url: "https://gitlab.example.com/group/repo.git", want: ProviderGitLab},
+		{name: "unknown host", url: "https://bitbucket.org/owner/repo.git", want: ""},
+		{name: "subdomain of a configured host is not configured", url: "https://x.code.acme.com/team/repo.git", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectProvider(tt.url); got != tt.want {
+				t.Errorf("DetectProvider(%q) = %q, want %q", tt.url, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetProviderHostsNilRestoresHeuristics(t *testing.T) {
+	SetProviderHosts(map[string]ProviderType{"code.acme.com": ProviderGitHub})
+	SetProviderHosts(nil)
+
+	if got := DetectProvider("https://code.acme.com/team/repo.git"); got != "" {
+		t.Errorf("DetectProvider() = %q after reset, want \"\"", got)
+	}
+}
+
+func TestParseProviderHosts(t *testing.T) {
+	got := ParseProviderHosts(" ghe.acme.com, CODE.acme.com ,,", "git.acme.com,ghe.acme.com")
+	want := map[string]ProviderType{
+		"ghe.acme.com":  ProviderGitLab,
+		"code.acme.com": ProviderGitHub,
+		"git.acme.com":  ProviderGitLab,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseProviderHosts() = %v, want %v", got, want)
+	}
+
+	if got := ParseProviderHosts("", ""); len(got) != 0 {
+		t.Errorf("ParseProviderHosts(\"\", \"\") = %v, want empty", got)
+	}
+}
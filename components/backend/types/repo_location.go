@@ -0,0 +1,156 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Checkout ref kinds returned by RepoLocation.CheckoutRef
+const (
+	RefKindCommit = "commit"
+	RefKindTag    = "tag"
+	RefKindBranch = "branch"
+)
+
+// ErrNoCheckoutRef is returned by CheckoutRef when no branch, tag or commit is set
+var ErrNoCheckoutRef = errors.New("repo location has no branch, tag or commit")
+
+// ErrConflictingRefs is returned by ValidateRefs when a location sets more than one ref
+var ErrConflictingRefs = errors.New("repo location sets conflicting refs")
+
+// ErrInvalidPRNumber is returned by RepoLocationFromPR for a zero or negative PR number
+var ErrInvalidPRNumber = errors.New("pull request number must be positive")
+
+// RepoLocation identifies a Git repository and an optional ref within it
+type RepoLocation struct {
+	URL    string  `json:"url"`
+	Branch *string `json:"branch,omitempty"`
+	Tag    *string `json:"tag,omitempty"`
+	Commit *string `json:"commit,omitempty"`
+	// CreateBranch applies to output locations: when true (or nil) a missing Branch is created
+	// from the input HEAD at push time; when false the branch must already exist
+	CreateBranch *bool `json:"createBranch,omitempty"`
+	// PullRequest marks Branch as a provider pull/merge request ref (e.g. refs/pull/42/head)
+	// Such refs are not advertised as branches, so clones must fetch them explicitly
+	PullRequest bool `json:"pullRequest,omitempty"`
+	// BranchFallbacks applies to input locations: branches to try in order when Branch is unset
+	// or does not exist on the remote (e.g. ["release", "main"]); see CandidateBranches
+	BranchFallbacks []string `json:"branchFallbacks,omitempty"`
+	// BaseCommit applies to input locations: a commit the agent already has, so the clone only
+	// needs to fetch the range from it to the head of the branch (nil = fetch as usual)
+	BaseCommit *string `json:"baseCommit,omitempty"`
+	// CredentialsSecretRef names the secret key holding the token for this location, e.g. a
+	// read-only token on the input and a push token on the output (nil = the session's default
+	// credentials); see SimpleRepo.InputCredentials and OutputCredentials for the fallback
+	CredentialsSecretRef *SecretRef `json:"credentialsSecretRef,omitempty"`
+}
+
+// RepoLocationFromPR returns a location that checks out the head of a pull request
+// GitHub uses refs/pull/<n>/head and GitLab refs/merge-requests/<n>/head; unknown
+// providers get the GitHub form, which most other forges (e.g. Gitea) also serve
+func RepoLocationFromPR(url string, prNumber int) (RepoLocation, error) {
+	if prNumber <= 0 {
+		return RepoLocation{}, fmt.Errorf("%w: got %d", ErrInvalidPRNumber, prNumber)
+	}
+	ref := fmt.Sprintf("refs/pull/%d/head", prNumber)
+	if DetectProvider(url) == ProviderGitLab {
+		ref = fmt.Sprintf("refs/merge-requests/%d/head", prNumber)
+	}
+	return RepoLocation{URL: url, Branch: StringPtr(ref), PullRequest: true}, nil
+}
+
+// UnmarshalJSON decodes a RepoLocation, treating an empty or whitespace-only branch as unset
+// This matches the normalization ParseRepoMap applies to map input
+func (r *RepoLocation) UnmarshalJSON(data []byte) error {
+	type repoLocationAlias RepoLocation
+	var loc repoLocationAlias
+	if err := json.Unmarshal(data, &loc); err != nil {
+		return err
+	}
+	loc.Branch = normalizeBranch(loc.Branch)
+	*r = RepoLocation(loc)
+	return nil
+}
+
+// CheckoutRef returns the single ref to check out for this location
+// Precedence is commit > tag > branch; blank values are ignored
+func (r *RepoLocation) CheckoutRef() (kind, value string, err error) {
+	if r == nil {
+		return "", "", ErrNoCheckoutRef
+	}
+	if v := nonBlank(r.Commit); v != "" {
+		return RefKindCommit, v, nil
+	}
+	if v := nonBlank(r.Tag); v != "" {
+		return RefKindTag, v, nil
+	}
+	if v := nonBlank(r.Branch); v != "" {
+		return RefKindBranch, v, nil
+	}
+	return "", "", ErrNoCheckoutRef
+}
+
+// ValidateRefs checks that at most one of Branch, Tag and Commit is set; blank values do not count
+// Whether a tag or commit is on a branch, or a branch and tag of the same name point at the same
+// commit, can only be told from the remote, so two refs are never provably compatible here and
+// CheckoutRef's precedence would silently pick one. The error wraps ErrConflictingRefs and names
+// the conflicting fields
+func (r *RepoLocation) ValidateRefs() error {
+	if r == nil {
+		return nil
+	}
+	var set []string
+	for _, ref := range []struct {
+		field string
+		value *string
+	}{{"branch", r.Branch}, {"tag", r.Tag}, {"commit", r.Commit}} {
+		if nonBlank(ref.value) != "" {
+			set = append(set, ref.field)
+		}
+	}
+	if len(set) > 1 {
+		return fmt.Errorf("%w: %s", ErrConflictingRefs, strings.Join(set, ", "))
+	}
+	return nil
+}
+
+// CandidateBranches returns the branches to try when cloning, in order: Branch (if set)
+// followed by BranchFallbacks, skipping blanks and repeats
+// The clone uses the first one that exists on the remote
+func (r *RepoLocation) CandidateBranches() []string {
+	if r == nil {
+		return nil
+	}
+	var candidates []string
+	for _, branch := range append([]string{nonBlank(r.Branch)}, r.BranchFallbacks...) {
+		if strings.TrimSpace(branch) == "" || slices.Contains(candidates, branch) {
+			continue
+		}
+		candidates = append(candidates, branch)
+	}
+	return candidates
+}
+
+// ShouldCreateBranch reports whether a missing branch may be created at push time (default true)
+func (r *RepoLocation) ShouldCreateBranch() bool {
+	return r == nil || r.CreateBranch == nil || *r.CreateBranch
+}
+
+// normalizeBranch returns nil for a nil, empty or whitespace-only branch
+func normalizeBranch(branch *string) *string {
+	if branch == nil || strings.TrimSpace(*branch) == "" {
+		return nil
+	}
+	return branch
+}
+
+// nonBlank dereferences s, returning "" for nil or whitespace-only values
+func nonBlank(s *string) string {
+	if s == nil || strings.TrimSpace(*s) == "" {
+		return ""
+	}
+	return *s
+}
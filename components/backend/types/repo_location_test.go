@@ -0,0 +1,168 @@
+package types
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRepoLocationCheckoutRef(t *testing.T) {
+	tests := []struct {
+		name      string
+		loc       *RepoLocation
+		wantKind  string
+		wantValue string
+		wantErr   bool
+	}{
+		{
+			name:      "commit wins over tag and branch",
+			loc:       &RepoLocation{Commit: StringPtr("abc123"), Tag: StringPtr("v1.0.0"), Branch: StringPtr("main")},
+			wantKind:  RefKindCommit,
+			wantValue: "abc123",
+		},
+		{
+			name:      "tag wins over branch",
+			loc:       &RepoLocation{Tag: StringPtr("v1.0.0"), Branch: StringPtr("main")},
+			wantKind:  RefKindTag,
+			wantValue: "v1.0.0",
+		},
+		{
+			name:      "branch only",
+			loc:       &RepoLocation{Branch: StringPtr("main")},
+			wantKind:  RefKindBranch,
+			wantValue: "main",
+		},
+		{
+			name:      "blank commit falls through to branch",
+			loc:       &RepoLocation{Commit: StringPtr("  "), Branch: StringPtr("main")},
+			wantKind:  RefKindBranch,
+			wantValue: "main",
+		},
+		{
+			name:    "all nil",
+			loc:     &RepoLocation{URL: "https://github.com/owner/repo.git"},
+			wantErr: true,
+		},
+		{
+			name:    "nil location",
+			loc:     nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kind, value, err := tt.loc.CheckoutRef()
+			if tt.wantErr {
+				if !errors.Is(err, ErrNoCheckoutRef) {
+					t.Fatalf("CheckoutRef() error = %v, want ErrNoCheckoutRef", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CheckoutRef() unexpected error: %v", err)
+			}
+			if kind != tt.wantKind || value != tt.wantValue {
+				t.Errorf("CheckoutRef() = (%q, %q), want (%q, %q)", kind, value, tt.wantKind, tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestSimpleRepoCreatesOutputBranch(t *testing.T) {
+	tests := []struct {
+		name string
+		repo SimpleRepo
+		want bool
+	}{
+		{name: "no output", repo: SimpleRepo{URL: "https://github.com/owner/repo.git"}, want: false},
+		{name: "createBranch unset defaults to true", repo: SimpleRepo{Output: &RepoLocation{URL: "https://github.com/fork/repo.git"}}, want: true},
+		{name: "createBranch true", repo: SimpleRepo{Output: &RepoLocation{URL: "https://github.com/fork/repo.git", CreateBranch: BoolPtr(true)}}, want: true},
+		{name: "createBranch false", repo: SimpleRepo{Output: &RepoLocation{URL: "https://github.com/fork/repo.git", CreateBranch: BoolPtr(false)}}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.repo.CreatesOutputBranch(); got != tt.want {
+				t.Errorf("CreatesOutputBranch() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRepoLocationFromPR(t *testing.T) {
+	tests := []struct {
+		name       string
+		url        string
+		prNumber   int
+		wantBranch string
+		wantErr    bool
+	}{
+		{name: "github", url: "https://github.com/owner/repo.git", prNumber: 42, wantBranch: "refs/pull/42/head"},
+		{name: "github ssh", url: "git@github.com:owner/repo.git", prNumber: 7, wantBranch: "refs/pull/7/head"},
+		{name: "github enterprise", url: "https://github.example.com/owner/repo", prNumber: 1, wantBranch: "refs/pull/1/head"},
+		{name: "gitlab merge request", url: "https://gitlab.com/group/repo.git", prNumber: 42, wantBranch: "refs/merge-requests/42/head"},
+		{name: "unknown provider uses github form", url: "https://git.example.com/owner/repo.git", prNumber: 3, wantBranch: "refs/pull/3/head"},
+		{name: "zero", url: "https://github.com/owner/repo.git", prNumber: 0, wantErr: true},
+		{name: "negative", url: "https://github.com/owner/repo.git", prNumber: -5, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loc, err := RepoLocationFromPR(tt.url, tt.prNumber)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidPRNumber) {
+					t.Fatalf("RepoLocationFromPR() error = %v, want ErrInvalidPRNumber", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RepoLocationFromPR() unexpected error: %v", err)
+			}
+			if loc.URL != tt.url {
+				t.Errorf("URL = %q, want %q", loc.URL, tt.url)
+			}
+			if !loc.PullRequest {
+				t.Error("PullRequest = false, want true")
+			}
+			kind, value, err := loc.CheckoutRef()
+			if err != nil || kind != RefKindBranch || value != tt.wantBranch {
+				t.Errorf("CheckoutRef() = (%q, %q, %v), want (%q, %q, nil)", kind, value, err, RefKindBranch, tt.wantBranch)
+			}
+		})
+	}
+}
+
+func TestRepoLocationValidateRefs(t *testing.T) {
+	tests := []struct {
+		name       string
+		loc        *RepoLocation
+		wantFields string
+	}{
+		{name: "branch only", loc: &RepoLocation{Branch: StringPtr("main")}},
+		{name: "tag only", loc: &RepoLocation{Tag: StringPtr("v1.0")}},
+		{name: "commit only", loc: &RepoLocation{Commit: StringPtr("0123456789abcdef0123456789abcdef01234567")}},
+		{name: "no refs", loc: &RepoLocation{URL: "https://github.com/owner/repo.git"}},
+		{name: "nil location", loc: nil},
+		{name: "blank tag alongside branch", loc: &RepoLocation{Branch: StringPtr("main"), Tag: StringPtr(" ")}},
+		{name: "branch and tag with the same name", loc: &RepoLocation{Branch: StringPtr("v1.0"), Tag: StringPtr("v1.0")}, wantFields: "branch, tag"},
+		{name: "branch and commit", loc: &RepoLocation{Branch: StringPtr("main"), Commit: StringPtr("abc123")}, wantFields: "branch, commit"},
+		{name: "all three", loc: &RepoLocation{Branch: StringPtr("main"), Tag: StringPtr("v1.0"), Commit: StringPtr("abc123")}, wantFields: "branch, tag, commit"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.loc.ValidateRefs()
+			if tt.wantFields == "" {
+				if err != nil {
+					t.Fatalf("ValidateRefs() unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrConflictingRefs) {
+				t.Fatalf("ValidateRefs() error = %v, want ErrConflictingRefs", err)
+			}
+			if want := "repo location sets conflicting refs: " + tt.wantFields; err.Error() != want {
+				t.Errorf("ValidateRefs() error = %q, want %q", err, want)
+			}
+		})
+	}
+}
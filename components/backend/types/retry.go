@@ -0,0 +1,35 @@
+package types
+
+import "time"
+
+// Bounds for RetryConfig.MaxAttempts; more than a handful of attempts only delays reporting
+// a failure that is not transient
+const (
+	MinRetryAttempts = 1
+	MaxRetryAttempts = 10
+)
+
+// RetryConfig tunes how a repo's clone and push are retried on failure
+// Each unset field falls back to the global setting
+type RetryConfig struct {
+	// MaxAttempts counts the first try, so 1 disables retries
+	MaxAttempts *int `json:"maxAttempts,omitempty"`
+	// InitialDelayMs is the wait before the first retry in milliseconds; later waits grow exponentially
+	InitialDelayMs *int `json:"initialDelayMs,omitempty"`
+}
+
+// InitialDelay returns InitialDelayMs as a duration, or def when it is unset
+func (c *RetryConfig) InitialDelay(def time.Duration) time.Duration {
+	if c == nil || c.InitialDelayMs == nil {
+		return def
+	}
+	return time.Duration(*c.InitialDelayMs) * time.Millisecond
+}
+
+// Attempts returns MaxAttempts, or def when it is unset
+func (c *RetryConfig) Attempts(def int) int {
+	if c == nil || c.MaxAttempts == nil {
+		return def
+	}
+	return *c.MaxAttempts
+}
@@ -1,5 +1,13 @@
 package types
 
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"time"
+)
+
 // AgenticSession represents the structure of our custom resource
 type AgenticSession struct {
 	APIVersion string                 `json:"apiVersion"`
@@ -22,6 +30,7 @@ type AgenticSessionSpec struct {
 	BotAccount           *BotAccountRef     `json:"botAccount,omitempty"`
 	ResourceOverrides    *ResourceOverrides `json:"resourceOverrides,omitempty"`
 	EnvironmentVariables map[string]string  `json:"environmentVariables,omitempty"`
+	Env                  []EnvVar           `json:"env,omitempty"`
 	Project              string             `json:"project,omitempty"`
 	// Multi-repo support
 	Repos []SimpleRepo `json:"repos,omitempty"`
@@ -30,15 +39,207 @@ type AgenticSessionSpec struct {
 }
 
 // SimpleRepo represents a simplified repository configuration
+// URL and Branch are the flat (V1) form; Input and Output are the V2 form
+// When parsed via ParseRepoMap, URL and Branch always mirror Input
 type SimpleRepo struct {
-	URL      string  `json:"url"`
-	Branch   *string `json:"branch,omitempty"`
-	AutoPush *bool   `json:"autoPush,omitempty"`
+	URL      string        `json:"url"`
+	Branch   *string       `json:"branch,omitempty"`
+	Input    *RepoLocation `json:"input,omitempty"`
+	Output   *RepoLocation `json:"output,omitempty"`
+	AutoPush *bool         `json:"autoPush,omitempty"`
+	// AutoPushOptions holds the object form of autoPush (nil when autoPush was a bare bool)
+	// Its Enabled always mirrors AutoPush
+	AutoPushOptions *AutoPushSpec `json:"autoPushOptions,omitempty"`
+	// RecurseSubmodules requests --recurse-submodules at clone time (nil = runner default)
+	RecurseSubmodules *bool `json:"recurseSubmodules,omitempty"`
+	// ReadOnly marks the repo as reference material: no output is allowed and the clone is mounted read-only
+	ReadOnly *bool `json:"readOnly,omitempty"`
+	// Primary marks the agent's main working repo; at most one repo may set it (nil = not marked,
+	// and the first repo is primary when none is marked)
+	Primary *bool `json:"primary,omitempty"`
+	// Dir names the checkout directory relative to the workspace (nil = derived from the repo name)
+	Dir *string `json:"dir,omitempty"`
+	// CloneTimeout bounds the clone of this repo (nil = global default)
+	CloneTimeout *Duration `json:"cloneTimeout,omitempty"`
+	// CloneFilter selects a partial clone: one of the CloneFilter* values (nil = CloneFilterFull)
+	CloneFilter *string `json:"cloneFilter,omitempty"`
+	// Depth requests a shallow clone of that many commits (nil = full history); see EffectiveDepth
+	Depth *int `json:"depth,omitempty"`
+	// Retries tunes retrying this repo's clone and push (nil = global retry settings)
+	Retries *RetryConfig `json:"retries,omitempty"`
+	// Priority orders clones when concurrency is limited: higher clones first (0 = default)
+	Priority int `json:"priority,omitempty"`
+	// DisplayName is a short label for the UI (nil = derived from the URL, see handlers.RepoDisplayName)
+	DisplayName *string `json:"displayName,omitempty"`
+	// Labels are Kubernetes labels to put on the session's pod, e.g. for cost attribution by team
+	Labels map[string]string `json:"labels,omitempty"`
+	// IncludePaths and ExcludePaths are relative glob patterns scoping which paths the agent may edit
+	// (empty IncludePaths = the whole repo); enforced by downstream tooling, not at clone time
+	IncludePaths []string `json:"includePaths,omitempty"`
+	ExcludePaths []string `json:"excludePaths,omitempty"`
+}
+
+// AutoPushSpec tunes how a repo's changes are pushed
+// A bare autoPush bool is equivalent to setting only Enabled
+type AutoPushSpec struct {
+	Enabled *bool `json:"enabled,omitempty"`
+	// Squash pushes the session's work as a single commit
+	Squash *bool `json:"squash,omitempty"`
+	// IntervalSeconds pushes periodically while the session runs instead of only at the end
+	IntervalSeconds *int `json:"intervalSeconds,omitempty"`
+	// PushCondition gates each push on a validation command (nil = push unconditionally)
+	PushCondition *PushCondition `json:"pushCondition,omitempty"`
+}
+
+// PushCondition is a command run in the checkout before pushing; the push happens only if it succeeds
+type PushCondition struct {
+	Command string `json:"command"`
+	// SuccessExitCodes are the exit codes that count as success (empty = only 0)
+	SuccessExitCodes []int `json:"successExitCodes,omitempty"`
+}
+
+// Succeeded reports whether exitCode counts as success for this condition
+func (c *PushCondition) Succeeded(exitCode int) bool {
+	if len(c.SuccessExitCodes) == 0 {
+		return exitCode == 0
+	}
+	return slices.Contains(c.SuccessExitCodes, exitCode)
+}
+
+// UnmarshalJSON decodes a SimpleRepo into the form ParseRepoMap returns for the same map
+// Blank branches become nil, Input and the flat URL/Branch fields are kept in sync, and the flat
+// form's branchFallbacks, baseCommit and credentialsSecretRef go to Input
+// autoPush may be a bool or an object ({enabled, squash, intervalSeconds, pushCondition}), under any
+// of the keys ParseRepoMap accepts (autoPush, autopush, auto_push); differing values are a conflict
+// Only types are checked: value rules (ranges, path and label syntax, output differing from input,
+// readOnly without output) and output branch inheritance are left to ParseRepoMap, so requests
+// must be validated with it rather than by decoding
+func (r *SimpleRepo) UnmarshalJSON(data []byte) error {
+	type simpleRepoAlias SimpleRepo
+	var repo struct {
+		simpleRepoAlias
+		// Shadows the alias field so both forms of autoPush reach decodeAutoPush
+		AutoPush      json.RawMessage `json:"autoPush"`
+		AutoPushLower json.RawMessage `json:"autopush"`
+		AutoPushSnake json.RawMessage `json:"auto_push"`
+	}
+	if err := json.Unmarshal(data, &repo); err != nil {
+		return err
+	}
+	decoded := SimpleRepo(repo.simpleRepoAlias)
+	decoded.Branch = normalizeBranch(decoded.Branch)
+	if decoded.Input != nil {
+		decoded.URL = decoded.Input.URL
+		decoded.Branch = decoded.Input.Branch
+	} else if decoded.URL != "" {
+		var flat RepoLocation
+		if err := json.Unmarshal(data, &flat); err != nil {
+			return err
+		}
+		decoded.Input = &RepoLocation{
+			URL:                  decoded.URL,
+			Branch:               decoded.Branch,
+			BranchFallbacks:      flat.BranchFallbacks,
+			BaseCommit:           flat.BaseCommit,
+			CredentialsSecretRef: flat.CredentialsSecretRef,
+		}
+	}
+	var autoPush *AutoPushSpec
+	var from string
+	for _, alias := range []struct {
+		key string
+		raw json.RawMessage
+	}{{"autoPush", repo.AutoPush}, {"autopush", repo.AutoPushLower}, {"auto_push", repo.AutoPushSnake}} {
+		spec, err := decodeAutoPush(alias.key, alias.raw)
+		if err != nil {
+			return err
+		}
+		if spec == nil {
+			continue
+		}
+		if autoPush == nil {
+			autoPush, from = spec, alias.key
+			continue
+		}
+		if !reflect.DeepEqual(autoPush, spec) {
+			return fmt.Errorf("%s conflicts with %s", alias.key, from)
+		}
+	}
+	if autoPush != nil {
+		decoded.AutoPush = autoPush.Enabled
+		if autoPush.Squash != nil || autoPush.IntervalSeconds != nil || autoPush.PushCondition != nil {
+			decoded.AutoPushOptions = autoPush
+		}
+	}
+	*r = decoded
+	return nil
+}
+
+// decodeAutoPush decodes an autoPush value found under key as a bool or an object; nil when absent
+// A bare bool sets only Enabled, as in ParseRepoMap
+func decodeAutoPush(key string, raw json.RawMessage) (*AutoPushSpec, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	var enabled bool
+	if err := json.Unmarshal(raw, &enabled); err == nil {
+		return &AutoPushSpec{Enabled: BoolPtr(enabled)}, nil
+	}
+	var spec AutoPushSpec
+	if err := json.Unmarshal(raw, &spec); err != nil {
+		return nil, fmt.Errorf("%s must be a boolean or an object: %w", key, err)
+	}
+	return &spec, nil
+}
+
+// CreatesOutputBranch reports whether the push step may create a missing output branch
+// False when the repo has no output
+func (r SimpleRepo) CreatesOutputBranch() bool {
+	return r.Output != nil && r.Output.ShouldCreateBranch()
+}
+
+// CloneTimeoutOr returns the repo's clone timeout, or def when none is set
+func (r SimpleRepo) CloneTimeoutOr(def time.Duration) time.Duration {
+	if r.CloneTimeout != nil {
+		return r.CloneTimeout.Duration
+	}
+	return def
+}
+
+// InputLocation returns the location to clone from
+// Falls back to the flat URL/Branch fields when Input is not set
+func (r SimpleRepo) InputLocation() RepoLocation {
+	if r.Input != nil {
+		return *r.Input
+	}
+	return RepoLocation{URL: r.URL, Branch: r.Branch}
+}
+
+// InputCredentials returns the secret ref used to clone the input
+// When only the output names one it applies to both locations (nil = default credentials)
+func (r SimpleRepo) InputCredentials() *SecretRef {
+	if ref := r.InputLocation().CredentialsSecretRef; ref != nil {
+		return ref
+	}
+	if r.Output != nil {
+		return r.Output.CredentialsSecretRef
+	}
+	return nil
+}
+
+// OutputCredentials returns the secret ref used to push to the output
+// When only the input names one it applies to both locations (nil = default credentials)
+func (r SimpleRepo) OutputCredentials() *SecretRef {
+	if r.Output != nil && r.Output.CredentialsSecretRef != nil {
+		return r.Output.CredentialsSecretRef
+	}
+	return r.InputLocation().CredentialsSecretRef
 }
 
 type AgenticSessionStatus struct {
 	ObservedGeneration int64               `json:"observedGeneration,omitempty"`
 	Phase              string              `json:"phase,omitempty"`
+	Message            string              `json:"message,omitempty"`
 	StartTime          *string             `json:"startTime,omitempty"`
 	CompletionTime     *string             `json:"completionTime,omitempty"`
 	ReconciledRepos    []ReconciledRepo    `json:"reconciledRepos,omitempty"`
@@ -55,10 +256,15 @@ type CreateAgenticSessionRequest struct {
 	Timeout         *int         `json:"timeout,omitempty"`
 	Interactive     *bool        `json:"interactive,omitempty"`
 	ParentSessionID string       `json:"parent_session_id,omitempty"`
-	// Multi-repo support
-	Repos                []SimpleRepo      `json:"repos,omitempty"`
+	// SessionTimeout bounds the whole session's wall-clock time (Go duration, e.g. "2h")
+	// Enforced by the operator; unlike Timeout it is not passed to the runner
+	SessionTimeout string `json:"sessionTimeout,omitempty"`
+	// Repos are kept in map form; CreateSession parses each entry with handlers.ParseRepoMap so
+	// a create applies the same repo rules, with per-entry errors, as every other repo consumer
+	Repos                []interface{}     `json:"repos,omitempty"`
 	UserContext          *UserContext      `json:"userContext,omitempty"`
 	EnvironmentVariables map[string]string `json:"environmentVariables,omitempty"`
+	Env                  []EnvVar          `json:"env,omitempty"`
 	Labels               map[string]string `json:"labels,omitempty"`
 	Annotations          map[string]string `json:"annotations,omitempty"`
 }
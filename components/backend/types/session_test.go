@@ -0,0 +1,101 @@
+package types
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestSimpleRepoJSONAutoPush(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		wantEnabled *bool
+		wantOptions *AutoPushSpec
+	}{
+		{"absent", `{"url": "https://github.com/owner/repo.git"}`, nil, nil},
+		{"null", `{"url": "https://github.com/owner/repo.git", "autoPush": null}`, nil, nil},
+		{"bool", `{"url": "https://github.com/owner/repo.git", "autoPush": true}`, BoolPtr(true), nil},
+		{"object with only enabled", `{"url": "https://github.com/owner/repo.git", "autoPush": {"enabled": false}}`, BoolPtr(false), nil},
+		{
+			"object with options",
+			`{"url": "https://github.com/owner/repo.git", "autoPush": {"enabled": true, "squash": true, "intervalSeconds": 300}}`,
+			BoolPtr(true),
+			&AutoPushSpec{Enabled: BoolPtr(true), Squash: BoolPtr(true), IntervalSeconds: IntPtr(300)},
+		},
+		{
+			"object with push condition",
+			`{"url": "https://github.com/owner/repo.git", "autoPush": {"enabled": true, "pushCondition": {"command": "make test"}}}`,
+			BoolPtr(true),
+			&AutoPushSpec{Enabled: BoolPtr(true), PushCondition: &PushCondition{Command: "make test"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var repo SimpleRepo
+			if err := json.Unmarshal([]byte(tt.input), &repo); err != nil {
+				t.Fatalf("Unmarshal() error: %v", err)
+			}
+			if !reflect.DeepEqual(repo.AutoPush, tt.wantEnabled) {
+				t.Errorf("AutoPush = %v, want %v", repo.AutoPush, tt.wantEnabled)
+			}
+			if !reflect.DeepEqual(repo.AutoPushOptions, tt.wantOptions) {
+				t.Errorf("AutoPushOptions = %+v, want %+v", repo.AutoPushOptions, tt.wantOptions)
+			}
+		})
+	}
+}
+
+func TestSimpleRepoJSONAutoPushRoundTrip(t *testing.T) {
+	want := SimpleRepo{
+		URL:             "https://github.com/owner/repo.git",
+		Input:           &RepoLocation{URL: "https://github.com/owner/repo.git"},
+		AutoPush:        BoolPtr(true),
+		AutoPushOptions: &AutoPushSpec{Enabled: BoolPtr(true), Squash: BoolPtr(true)},
+	}
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatalf("Marshal() error: %v", err)
+	}
+	var got SimpleRepo
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal() error: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip = %+v, want %+v", got, want)
+	}
+}
+
+func TestSimpleRepoJSONAutoPushRejectsInvalid(t *testing.T) {
+	for _, input := range []string{
+		`{"url": "https://github.com/owner/repo.git", "autoPush": "yes"}`,
+		`{"url": "https://github.com/owner/repo.git", "autoPush": {"enabled": "yes"}}`,
+	} {
+		var repo SimpleRepo
+		if err := json.Unmarshal([]byte(input), &repo); err == nil {
+			t.Errorf("Unmarshal(%s) = %+v, want error", input, repo)
+		}
+	}
+}
+
+func TestSimpleRepoJSONAutoPushAliases(t *testing.T) {
+	for _, input := range []string{
+		`{"url": "https://github.com/owner/repo.git", "autopush": true}`,
+		`{"url": "https://github.com/owner/repo.git", "auto_push": true}`,
+		`{"url": "https://github.com/owner/repo.git", "autoPush": true, "auto_push": {"enabled": true}}`,
+	} {
+		var repo SimpleRepo
+		if err := json.Unmarshal([]byte(input), &repo); err != nil {
+			t.Fatalf("Unmarshal(%s) error: %v", input, err)
+		}
+		if repo.AutoPush == nil || !*repo.AutoPush {
+			t.Errorf("Unmarshal(%s) AutoPush = %v, want true", input, repo.AutoPush)
+		}
+	}
+
+	var repo SimpleRepo
+	err := json.Unmarshal([]byte(`{"url": "https://github.com/owner/repo.git", "autoPush": true, "autopush": false}`), &repo)
+	if err == nil || err.Error() != "autopush conflicts with autoPush" {
+		t.Errorf("Unmarshal() error = %v, want autopush conflicts with autoPush", err)
+	}
+}
@@ -121,6 +121,7 @@ func HandleAGUIRunProxy(c *gin.Context) {
 	runnerURL, err := getRunnerEndpoint(projectName, sessionName)
 	if err != nil {
 		log.Printf("AGUI Proxy: Failed to get runner endpoint: %v", err)
+		handlers.SetRetryAfter(c.Writer, handlers.DefaultRetryAfter)
 		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Runner not available"})
 		return
 	}
@@ -370,6 +371,7 @@ func HandleAGUIInterrupt(c *gin.Context) {
 	runnerURL, err := getRunnerEndpoint(projectName, sessionName)
 	if err != nil {
 		log.Printf("AGUI Interrupt: Failed to get runner endpoint: %v", err)
+		handlers.SetRetryAfter(c.Writer, handlers.DefaultRetryAfter)
 		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Runner not available"})
 		return
 	}
@@ -398,6 +400,7 @@ func HandleAGUIInterrupt(c *gin.Context) {
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		log.Printf("AGUI Interrupt: Runner returned %d: %s", resp.StatusCode, string(body))
+		handlers.ForwardRetryAfter(c.Writer, resp)
 		c.JSON(resp.StatusCode, gin.H{"error": string(body)})
 		return
 	}
@@ -445,6 +448,7 @@ func HandleMCPStatus(c *gin.Context) {
 	runnerURL, err := getRunnerEndpoint(projectName, sessionName)
 	if err != nil {
 		log.Printf("MCP Status: Failed to get runner endpoint: %v", err)
+		handlers.SetRetryAfter(c.Writer, handlers.DefaultRetryAfter)
 		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Runner not available"})
 		return
 	}
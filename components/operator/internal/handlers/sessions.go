@@ -25,6 +25,7 @@ import (
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	intstr "k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/util/retry"
 )
 
@@ -729,10 +730,7 @@ func handleAgenticSessionEvent(obj *unstructured.Unstructured) error {
 		ObjectMeta: v1.ObjectMeta{
 			Name:      podName,
 			Namespace: sessionNamespace,
-			Labels: map[string]string{
-				"agentic-session": name,
-				"app":             "ambient-code-runner",
-			},
+			Labels:    runnerPodLabels(name, spec),
 			// If you run a service mesh that injects sidecars and causes egress issues:
 			// Annotations: map[string]string{"sidecar.istio.io/inject": "false"},
 			OwnerReferences: []v1.OwnerReference{
@@ -2303,6 +2301,36 @@ func deriveRepoNameFromURL(repoURL string) string {
 	return "repo"
 }
 
+// runnerPodLabels returns the runner pod's labels: every repo's labels from spec.repos, plus the
+// session labels the operator selects pods by, which repo labels cannot override
+// Repos are merged in list order and the first repo to set a key wins; a key or value that is not
+// valid label syntax is skipped, since the backend already rejects those when the session is created
+func runnerPodLabels(sessionName string, spec map[string]interface{}) map[string]string {
+	labels := map[string]string{}
+	repos, _ := spec["repos"].([]interface{})
+	for i, item := range repos {
+		repo, _ := item.(map[string]interface{})
+		repoLabels, _ := repo["labels"].(map[string]interface{})
+		for key, raw := range repoLabels {
+			value, ok := raw.(string)
+			if !ok || len(validation.IsQualifiedName(key)) > 0 || len(validation.IsValidLabelValue(value)) > 0 {
+				log.Printf("Skipping invalid label %q on repos[%d] of session %s", key, i, sessionName)
+				continue
+			}
+			if existing, taken := labels[key]; taken {
+				if existing != value {
+					log.Printf("Ignoring label %s=%s on repos[%d] of session %s: an earlier repo set %s", key, value, i, sessionName, existing)
+				}
+				continue
+			}
+			labels[key] = value
+		}
+	}
+	labels["agentic-session"] = sessionName
+	labels["app"] = "ambient-code-runner"
+	return labels
+}
+
 // pollRunnerReposStatus removed - frontend now polls runner directly via backend API
 // for real-time branch information. Operator no longer needs to maintain this in CR status.
 
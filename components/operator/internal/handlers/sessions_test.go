@@ -596,3 +596,46 @@ func TestDeleteAmbientVertexSecret_NilAnnotations(t *testing.T) {
 		t.Error("Secret should still exist")
 	}
 }
+
+// TestRunnerPodLabels verifies repo labels reach the runner pod without displacing the session labels
+func TestRunnerPodLabels(t *testing.T) {
+	spec := map[string]interface{}{
+		"repos": []interface{}{
+			map[string]interface{}{
+				"url":    "https://github.com/owner/app.git",
+				"labels": map[string]interface{}{"team": "infra", "app": "billing", "cost-center": "42"},
+			},
+			map[string]interface{}{
+				"url":    "https://github.com/owner/lib.git",
+				"labels": map[string]interface{}{"team": "platform", "example.com/component": "lib", "bad key!": "x", "tier": "not valid!"},
+			},
+			map[string]interface{}{"url": "https://github.com/owner/docs.git"},
+		},
+	}
+
+	labels := runnerPodLabels("session-1", spec)
+
+	want := map[string]string{
+		"agentic-session":       "session-1",
+		"app":                   "ambient-code-runner",
+		"team":                  "infra",
+		"cost-center":           "42",
+		"example.com/component": "lib",
+	}
+	if len(labels) != len(want) {
+		t.Errorf("expected %d labels, got %v", len(want), labels)
+	}
+	for key, value := range want {
+		if labels[key] != value {
+			t.Errorf("expected label %s=%s, got %q", key, value, labels[key])
+		}
+	}
+}
+
+// TestRunnerPodLabels_NoRepos verifies a session without repos keeps only the session labels
+func TestRunnerPodLabels_NoRepos(t *testing.T) {
+	labels := runnerPodLabels("session-1", map[string]interface{}{})
+	if len(labels) != 2 || labels["agentic-session"] != "session-1" || labels["app"] != "ambient-code-runner" {
+		t.Errorf("expected only the session labels, got %v", labels)
+	}
+}